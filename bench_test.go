@@ -0,0 +1,93 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/csgura/di"
+	"github.com/csgura/fp"
+)
+
+type benchRepo struct{}
+type benchCache interface {
+	Get(key string) string
+}
+type benchCacheImpl struct{}
+
+func (r *benchCacheImpl) Get(key string) string {
+	return key
+}
+
+type benchHandler struct {
+	Repo  *benchRepo            `di:"inject"`
+	Cache benchCache            `di:"inject"`
+	Opt   fp.Option[benchCache] `di:"inject"`
+	Name  string
+}
+
+func benchInjector() di.Injector {
+	return benchImplements().NewInjector([]string{"Bench"})
+}
+
+func benchImplements() *di.Implements {
+	impls := di.NewImplements()
+	impls.AddImplement("Bench", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*benchRepo](binder).ToInstance(&benchRepo{})
+		di.Bind[benchCache](binder).ToInstance(&benchCacheImpl{})
+	}))
+	return impls
+}
+
+func BenchmarkInjectMembers(b *testing.B) {
+	injector := benchInjector()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		handler := benchHandler{}
+		injector.InjectMembers(&handler)
+	}
+}
+
+func BenchmarkInjectAndCall(b *testing.B) {
+	injector := benchInjector()
+	handle := func(repo *benchRepo, cache benchCache, opt fp.Option[benchCache]) {}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		injector.InjectAndCall(handle)
+	}
+}
+
+func BenchmarkGetSingleton(b *testing.B) {
+	injector := benchInjector()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		di.GetInstance[benchCache](injector)
+	}
+}
+
+// BenchmarkGetSingletonTraced uses full injector context because trace callback should see every request
+func BenchmarkGetSingletonTraced(b *testing.B) {
+	impls := di.NewImplements()
+	impls.AddImplement("Bench", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[benchCache](binder).ToInstance(&benchCacheImpl{})
+	}))
+	injector := impls.NewInjectorWithTrace([]string{"Bench"}, func(info *di.TraceInfo) {})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		di.GetInstance[benchCache](injector)
+	}
+}
+
+func BenchmarkGetSingletonParallel(b *testing.B) {
+	injector := benchInjector()
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			di.GetInstance[*benchRepo](injector)
+		}
+	})
+}
@@ -1,8 +1,12 @@
 package di
 
 import (
+	"fmt"
 	"reflect"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 // Binding has provider function and created singlton instances
@@ -18,7 +22,55 @@ type Binding struct {
 	isDecoratorOf bool
 	isInterceptor bool
 	interceptor   interceptorProvider
+	conditions    []Condition
+
+	// groups are conditions of ConditionalModule installing the binding
+	groups []*conditionGroup
+
+	// ignoreDuplicate is set if duplicated bindings of the module configuring this are ignored.
+	// it is used when conditional binding is resolved
+	ignoreDuplicate bool
+
 	singletonOnce sync.Once
+
+	// seq is registration order of the binding
+	seq uint64
+
+	// dependencies are binding keys which are injected to the constructor
+	dependencies []reflect.Type
+
+	// refreshPatterns are patterns of properties which make the singleton recreated
+	refreshPatterns []string
+	refreshLock     sync.RWMutex
+
+	// refreshGen is increased by refresh and builtGen is refreshGen when the instance started to be created.
+	// instance is stale if they are different
+	refreshGen atomic.Uint64
+	builtGen   atomic.Uint64
+
+	// retry is policy to call provider again when it fails
+	retry *RetryPolicy
+
+	// module is name of module which configured the binding
+	module string
+
+	// topModule is name of enabled module which installed the module
+	topModule string
+
+	// moduleKey is identity of module which configured the binding. it is nil if the module has no identity
+	moduleKey interface{}
+
+	// source is file:line of code which configured the binding
+	source string
+
+	// counters are stats of the binding maintained by the injector
+	counters *bindingCounters
+
+	// ready is created singleton. it is read without lock by fast path
+	ready atomic.Pointer[readySingleton]
+
+	// created is set after singletonOnce completes creation, so creation registry is not needed
+	created atomic.Bool
 }
 
 // ToInstance binds type to singleton instance
@@ -58,6 +110,7 @@ func (b *Binding) ToConstructor(function interface{}) *Binding {
 		panic("Decorator can't bind to constructor")
 	}
 
+	b.dependencies = constructorDependencies(function)
 	return b.ToProvider(func(injector Injector) interface{} {
 		return injector.InjectAndCall(function)
 	})
@@ -85,11 +138,15 @@ type interceptorProvider func(injector Injector, instance interface{}) interface
 
 // Binder has bindings
 type Binder struct {
-	providers         map[reflect.Type]*Binding
-	providersFallback map[reflect.Type]*Binding
-	decorators        map[reflect.Type][]*Binding
-	interceptors      map[reflect.Type][]*Binding
-	ignoreDuplicate   bool
+	providers       map[reflect.Type]*Binding
+	conditionals    []*Binding
+	decorators      map[reflect.Type][]*Binding
+	interceptors    map[reflect.Type][]*Binding
+	ignoreDuplicate bool
+	modules         *moduleRegistry
+
+	// profiles are active profiles which are used to report duplicated bindings
+	profiles []activeProfile
 }
 
 func safeAppend(list []*Binding, b *Binding) []*Binding {
@@ -129,15 +186,12 @@ func (b *Binder) Bind(ptrToType interface{}) *Binding {
 	}
 }
 
-// IfNotBinded returns Binding that will used if there are no other binding for tpe type
+// IfNotBinded returns Binding that will used if there are no other binding for tpe type.
+// it is same as Bind(ptrToType).When(Missing(ptrToType)) except that it is evaluated after other conditional bindings
 func (b *Binder) IfNotBinded(ptrToType interface{}) *Binding {
-	t := reflect.TypeOf(ptrToType)
-	return &Binding{
-		binder:      b,
-		tpe:         t,
-		isSingleton: true,
-		isFallback:  true,
-	}
+	ret := b.Bind(ptrToType).When(Missing(ptrToType))
+	ret.isFallback = true
+	return ret
 }
 
 // AddDecoratorOf add customizing function which will be applied to the created singleton instance
@@ -156,20 +210,32 @@ func (b *Binder) AddDecoratorOf(ptrToType interface{}, decorator func(ij Injecto
 }
 
 func (b *Binder) bind(binding *Binding) {
+	if binding.seq == 0 {
+		binding.seq = bindingSeq.Add(1)
+	}
+
+	if binding.source == "" {
+		binding.source = bindingSource()
+		if b.modules != nil && len(b.modules.path) > 0 {
+			binding.module = b.modules.path[len(b.modules.path)-1]
+			binding.topModule = b.modules.path[0]
+			binding.moduleKey = b.modules.keys[len(b.modules.keys)-1]
+		}
+	}
+
 	if binding.isDecoratorOf {
 		b.addDecorator(binding)
 	} else {
 		t := binding.tpe
-		if binding.isFallback {
-			if b.providersFallback[t] == nil {
-				b.providersFallback[t] = binding
-			}
+		if len(binding.conditions) > 0 || len(binding.groups) > 0 {
+			binding.ignoreDuplicate = b.ignoreDuplicate
+			b.conditionals = safeAppend(b.conditionals, binding)
 		} else {
 			if b.providers[t] == nil {
 				b.providers[t] = binding
 			} else {
 				if !b.ignoreDuplicate {
-					panic("duplicated bind for " + t.String())
+					panic(b.duplicateError(b.providers[t], binding))
 				}
 			}
 		}
@@ -177,18 +243,34 @@ func (b *Binder) bind(binding *Binding) {
 
 }
 
+var diPackage = reflect.TypeOf(Binding{}).PkgPath() + "."
+
+// bindingSource returns file:line of the first caller outside of this package
+func bindingSource() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, diPackage) {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
 func (b *Binder) merge(other *Binder, panicOnDup bool) {
 	for k, v := range other.providers {
 		if b.providers[k] == nil {
 			b.providers[k] = v
 		} else if panicOnDup {
-			panic("duplicated bind for " + v.tpe.String())
+			panic(b.duplicateError(b.providers[k], v))
 		}
 	}
-	for k, v := range other.providersFallback {
-		if b.providersFallback[k] == nil {
-			b.providersFallback[k] = v
-		}
+	for _, v := range other.conditionals {
+		b.conditionals = safeAppend(b.conditionals, v)
 	}
 
 	for _, list := range other.decorators {
@@ -205,14 +287,6 @@ func (b *Binder) merge(other *Binder, panicOnDup bool) {
 
 }
 
-func (b *Binder) mergeFallbacks() {
-	for k, v := range b.providersFallback {
-		if b.providers[k] == nil {
-			b.providers[k] = v
-		}
-	}
-}
-
 // BindProvider binds intf type to provider function
 func (b *Binder) BindProvider(ptrToType interface{}, provider func(injector Injector) interface{}) *Binding {
 	return b.Bind(ptrToType).ToProvider(provider)
@@ -312,10 +386,17 @@ type AbstractModule interface {
 func newBinder() *Binder {
 	ret := new(Binder)
 	ret.providers = make(map[reflect.Type]*Binding)
-	ret.providersFallback = make(map[reflect.Type]*Binding)
 
 	ret.decorators = make(map[reflect.Type][]*Binding)
 	ret.interceptors = make(map[reflect.Type][]*Binding)
+	ret.modules = newModuleRegistry()
+
+	return ret
+}
 
+// newChildBinder returns new empty Binder which shares installed modules with this
+func (b *Binder) newChildBinder() *Binder {
+	ret := newBinder()
+	ret.modules = b.modules
 	return ret
 }
@@ -0,0 +1,267 @@
+package di
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ConditionContext is used to evaluate conditions of bindings.
+// it has properties and bindings of the injector being created
+type ConditionContext interface {
+	GetProperty(propName string) string
+	IsBinded(ptrToType interface{}) bool
+}
+
+// Condition decides whether a binding or a module is enabled
+type Condition func(ctx ConditionContext) bool
+
+// PropertyEquals returns condition which is satisfied if the property has the value
+func PropertyEquals(propName string, value string) Condition {
+	return func(ctx ConditionContext) bool {
+		return ctx.GetProperty(propName) == value
+	}
+}
+
+// Missing returns condition which is satisfied if there are no binding for the type
+func Missing(ptrToType interface{}) Condition {
+	return func(ctx ConditionContext) bool {
+		return !ctx.IsBinded(ptrToType)
+	}
+}
+
+// Present returns condition which is satisfied if there is a binding for the type
+func Present(ptrToType interface{}) Condition {
+	return func(ctx ConditionContext) bool {
+		return ctx.IsBinded(ptrToType)
+	}
+}
+
+type conditionContext struct {
+	binder *Binder
+	props  map[string]string
+
+	// usedBindings is set if evaluated condition asks bindings
+	usedBindings bool
+}
+
+func (r *conditionContext) GetProperty(propName string) string {
+	return r.props[propName]
+}
+
+func (r *conditionContext) IsBinded(ptrToType interface{}) bool {
+	r.usedBindings = true
+	return r.binder.providers[reflect.TypeOf(ptrToType)] != nil
+}
+
+// conditionGroup is condition of ConditionalModule shared by all bindings of the module
+// so that it is evaluated once for the whole module
+type conditionGroup struct {
+	condition Condition
+
+	// locked is set after a binding of the group is registered, then result doesn't change
+	locked bool
+	result bool
+}
+
+// eval returns result of condition and whether the result depends on bindings
+func (r *conditionContext) eval(condition Condition) (bool, bool) {
+	r.usedBindings = false
+	ret := condition(r)
+	return ret, r.usedBindings
+}
+
+// check returns whether all conditions of the binding are satisfied
+// and whether the result can be changed by other bindings
+func (r *conditionContext) check(binding *Binding) (bool, bool) {
+	dynamic := false
+	for _, g := range binding.groups {
+		if g.locked {
+			if !g.result {
+				return false, false
+			}
+			continue
+		}
+		ok, dyn := r.eval(g.condition)
+		if !ok {
+			return false, dyn
+		}
+		dynamic = dynamic || dyn
+	}
+
+	for _, cond := range binding.conditions {
+		ok, dyn := r.eval(cond)
+		if !ok {
+			return false, dyn
+		}
+		dynamic = dynamic || dyn
+	}
+	return true, dynamic
+}
+
+func (r *conditionContext) matches(binding *Binding) bool {
+	ok, _ := r.check(binding)
+	return ok
+}
+
+// When adds condition to the binding.
+// conditions are evaluated after all modules are configured.
+// it should be called before the binding is binded to provider, constructor or instance
+func (b *Binding) When(condition Condition) *Binding {
+	if b.provider != nil {
+		panic("When should be called before binding " + b.tpe.String())
+	}
+
+	b.conditions = append(b.conditions, condition)
+	return b
+}
+
+type conditionalModule struct {
+	condition Condition
+	modules   []AbstractModule
+}
+
+func (r *conditionalModule) Configure(binder *Binder) {
+	// modules are installed to separate registry, so that a module installed here
+	// is installed again if it is installed without condition
+	tempBinder := binder.newChildBinder()
+	tempBinder.modules = binder.modules.newConditional()
+	tempBinder.ignoreDuplicate = binder.ignoreDuplicate
+
+	for _, m := range r.modules {
+		tempBinder.Install(m)
+	}
+	binder.modules.list = append(binder.modules.list, tempBinder.modules.list...)
+
+	group := &conditionGroup{condition: r.condition}
+	addGroup := func(binding *Binding) {
+		binding.groups = append([]*conditionGroup{group}, binding.groups...)
+	}
+
+	for _, v := range tempBinder.providers {
+		addGroup(v)
+	}
+	for _, v := range tempBinder.conditionals {
+		addGroup(v)
+	}
+	for _, list := range tempBinder.decorators {
+		for _, v := range list {
+			addGroup(v)
+		}
+	}
+	for _, list := range tempBinder.interceptors {
+		for _, v := range list {
+			addGroup(v)
+		}
+	}
+
+	// every providers are conditional now
+	for _, v := range tempBinder.providers {
+		v.ignoreDuplicate = tempBinder.ignoreDuplicate
+		tempBinder.conditionals = append(tempBinder.conditionals, v)
+	}
+	tempBinder.providers = make(map[reflect.Type]*Binding)
+	sortBySeq(tempBinder.conditionals)
+
+	binder.merge(tempBinder, true)
+}
+
+func sortBySeq(bindings []*Binding) {
+	sort.SliceStable(bindings, func(i, j int) bool {
+		return bindings[i].seq < bindings[j].seq
+	})
+}
+
+// ConditionalModule returns a new module that installs all of modules only if the condition is satisfied.
+// the condition is evaluated after all modules are configured
+func ConditionalModule(condition Condition, modules ...AbstractModule) AbstractModule {
+	return &conditionalModule{condition: condition, modules: modules}
+}
+
+// resolveConditionals binds conditional bindings whose conditions are satisfied.
+// conditions not depending on other bindings are evaluated first.
+// then conditions like Present and Missing are evaluated in binding order repeatedly
+// until no more bindings are added, and fallback bindings are evaluated last.
+func (b *Binder) resolveConditionals(props map[string]string) {
+	ctx := &conditionContext{binder: b, props: props}
+
+	sortBySeq(b.conditionals)
+
+	register := func(binding *Binding) {
+		if existing := b.providers[binding.tpe]; existing != nil {
+			// same module is installed more than once, for example with and without condition
+			if !sameModule(existing, binding) && !binding.ignoreDuplicate {
+				panic(b.duplicateError(existing, binding))
+			}
+		} else {
+			b.providers[binding.tpe] = binding
+		}
+		for _, g := range binding.groups {
+			g.locked = true
+			g.result = true
+		}
+	}
+
+	var pending []*Binding
+	var fallbacks []*Binding
+	for _, binding := range b.conditionals {
+		if binding.isFallback {
+			fallbacks = append(fallbacks, binding)
+			continue
+		}
+
+		ok, dynamic := ctx.check(binding)
+		if dynamic {
+			pending = append(pending, binding)
+		} else if ok {
+			register(binding)
+		}
+	}
+
+	for changed := true; changed; {
+		changed = false
+		var rest []*Binding
+		for _, binding := range pending {
+			if ctx.matches(binding) {
+				register(binding)
+				changed = true
+			} else {
+				rest = append(rest, binding)
+			}
+		}
+		pending = rest
+	}
+
+	for _, binding := range fallbacks {
+		if ctx.matches(binding) {
+			register(binding)
+		}
+	}
+
+	for t, list := range b.decorators {
+		b.decorators[t] = b.filterMatched(ctx, list)
+	}
+
+	for t, list := range b.interceptors {
+		b.interceptors[t] = b.filterMatched(ctx, list)
+	}
+}
+
+// sameModule returns whether both bindings are configured by same module
+func sameModule(a *Binding, b *Binding) bool {
+	return a.moduleKey != nil && a.moduleKey == b.moduleKey
+}
+
+// filterMatched returns bindings whose conditions are satisfied.
+// bindings of a module installed by ConditionalModule are dropped if the module is also installed without condition
+func (b *Binder) filterMatched(ctx *conditionContext, list []*Binding) []*Binding {
+	var ret []*Binding
+	for _, v := range list {
+		if len(v.groups) > 0 && v.moduleKey != nil && b.modules.installed[v.moduleKey] != nil {
+			continue
+		}
+		if ctx.matches(v) {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
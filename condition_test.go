@@ -0,0 +1,172 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+func cacheModule(binder *di.Binder) {
+	binder.Bind((*Value1)(nil)).When(di.PropertyEquals("cache.kind", "redis")).ToInstance(&ValueImpl{"redis"})
+	binder.Bind((*Value1)(nil)).When(di.PropertyEquals("cache.kind", "memory")).ToInstance(&ValueImpl{"memory"})
+	binder.IfNotBinded((*Value1)(nil)).ToInstance(&ValueImpl{"default"})
+
+	binder.Bind((*Value2)(nil)).When(di.Present((*Value3)(nil))).ToInstance(&ValueImpl{"Value3 present"})
+	binder.Bind((*Value2)(nil)).When(di.Missing((*Value3)(nil))).ToInstance(&ValueImpl{"Value3 missing"})
+}
+
+func TestConditionalBinding(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddBind(cacheModule)
+	impls.SetProperty("cache.kind", "redis")
+
+	injector := impls.NewInjector(nil)
+	if injector.GetInstance((*Value1)(nil)).(Value1).Value() != "redis" {
+		t.Errorf("redis cache not binded")
+	}
+
+	if injector.GetInstance((*Value2)(nil)).(Value2).Value() != "Value3 missing" {
+		t.Errorf("missing condition not satisfied")
+	}
+
+	if injector.GetProperty("cache.kind") != "redis" {
+		t.Errorf("property not set to injector")
+	}
+
+	impls = di.NewImplements()
+	impls.AddBind(cacheModule)
+	impls.AddBind(func(binder *di.Binder) {
+		binder.Bind((*Value3)(nil)).ToInstance(&ValueImpl{"Value3"})
+	})
+
+	injector = impls.NewInjector(nil)
+	if injector.GetInstance((*Value1)(nil)).(Value1).Value() != "default" {
+		t.Errorf("fallback not binded")
+	}
+
+	if injector.GetInstance((*Value2)(nil)).(Value2).Value() != "Value3 present" {
+		t.Errorf("present condition not satisfied")
+	}
+}
+
+func TestConditionalModule(t *testing.T) {
+	redis := di.ConditionalModule(di.PropertyEquals("cache.kind", "redis"), di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"redis"})
+	}))
+
+	memory := di.ConditionalModule(di.PropertyEquals("cache.kind", "memory"), di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"memory"})
+	}))
+
+	impls := di.NewImplements()
+	impls.AddImplement("Redis", redis)
+	impls.AddImplement("Memory", memory)
+	impls.SetProperty("cache.kind", "memory")
+
+	injector := impls.NewInjector([]string{"Redis", "Memory"})
+	if injector.GetInstance((*Value1)(nil)).(Value1).Value() != "memory" {
+		t.Errorf("memory cache not binded")
+	}
+}
+
+func TestConditionalDuplicated(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+
+	impls := di.NewImplements()
+	impls.AddImplement("Cache", di.BindFunc(cacheModule))
+	impls.AddImplement("Redis", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"redis"})
+	}))
+	impls.SetProperty("cache.kind", "redis")
+
+	impls.NewInjector([]string{"Cache", "Redis"})
+}
+
+func TestConditionalModuleEvaluatedOnce(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		impls := di.NewImplements()
+		impls.AddImplement("Default", di.ConditionalModule(di.Missing((*Value1)(nil)), di.BindFunc(func(binder *di.Binder) {
+			binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"Value1"})
+			binder.Bind((*Value2)(nil)).ToInstance(&ValueImpl{"Value2"})
+		})))
+
+		injector := impls.NewInjector([]string{"Default"})
+		if injector.GetInstance((*Value1)(nil)) == nil || injector.GetInstance((*Value2)(nil)) == nil {
+			t.Fatal("bindings of the module are partially binded")
+		}
+	}
+}
+
+func TestConditionalPresentOrder(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddImplement("X", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value2)(nil)).When(di.Present((*Value1)(nil))).ToInstance(&ValueImpl{"Value2"})
+	}))
+	impls.AddImplement("Y", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).When(di.PropertyEquals("k", "v")).ToInstance(&ValueImpl{"Value1"})
+	}))
+	impls.SetProperty("k", "v")
+
+	injector := impls.NewInjector([]string{"X", "Y"})
+	if injector.GetInstance((*Value1)(nil)) == nil {
+		t.Error("Value1 is not binded")
+	}
+	if injector.GetInstance((*Value2)(nil)) == nil {
+		t.Error("Value2 is not binded although Value1 is present")
+	}
+}
+
+type value1Module struct {
+	decorated int
+}
+
+func (r *value1Module) Configure(binder *di.Binder) {
+	binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"Value1"})
+	binder.AddDecoratorOf((*Value1)(nil), func(ij di.Injector) {
+		r.decorated++
+	})
+}
+
+func TestConditionalModuleInstalledAgain(t *testing.T) {
+	for _, cond := range []string{"y", "z"} {
+		m := &value1Module{}
+		impls := di.NewImplements()
+		impls.AddImplement("Cond", di.ConditionalModule(di.PropertyEquals("x", "y"), m))
+		impls.AddImplement("Plain", m)
+		impls.SetProperty("x", cond)
+
+		injector := impls.NewInjector([]string{"Cond", "Plain"})
+		if injector.GetInstance((*Value1)(nil)) == nil {
+			t.Errorf("Value1 is not binded when x=%s", cond)
+		}
+		if m.decorated != 1 {
+			t.Errorf("decorator is called %d times when x=%s", m.decorated, cond)
+		}
+	}
+}
+
+func TestConditionalDuplicateOfAnonymousModules(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddBind(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"plain"})
+	})
+	impls.AddBind(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).When(di.PropertyEquals("k", "v")).ToInstance(&ValueImpl{"conditional"})
+	})
+	impls.AddBind(func(binder *di.Binder) {
+		binder.Install(di.ConditionalModule(di.PropertyEquals("k", "v"), di.BindFunc(func(binder *di.Binder) {
+			binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"module"})
+		})))
+	})
+	impls.SetProperty("k", "v")
+
+	injector := impls.NewInjector(nil)
+	if injector.GetInstance((*Value1)(nil)) == nil {
+		t.Error("Value1 is not binded")
+	}
+}
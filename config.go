@@ -0,0 +1,196 @@
+package di
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ConfigFormat is format of module selection configuration
+type ConfigFormat int
+
+const (
+	// JSONFormat is json object which has modules, profiles and properties fields
+	JSONFormat ConfigFormat = iota
+
+	// PropertiesFormat is key=value lines like java properties or env file.
+	// modules and profiles keys are comma separated lists
+	PropertiesFormat
+)
+
+// ModuleSelection is enabled module names and properties loaded from configuration
+type ModuleSelection struct {
+	Modules    []string
+	Profiles   []string
+	Properties map[string]string
+}
+
+type jsonModuleSelection struct {
+	Modules    []string               `json:"modules"`
+	Profiles   []string               `json:"profiles"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// LoadModuleSelection reads enabled module names and properties from r
+func LoadModuleSelection(r io.Reader, format ConfigFormat) (*ModuleSelection, error) {
+	switch format {
+	case JSONFormat:
+		var sel jsonModuleSelection
+		if err := json.NewDecoder(r).Decode(&sel); err != nil {
+			return nil, fmt.Errorf("invalid json module selection : %w", err)
+		}
+		props := map[string]string{}
+		flattenProperties("", sel.Properties, props)
+		return &ModuleSelection{sel.Modules, sel.Profiles, props}, nil
+	case PropertiesFormat:
+		props, err := parseProperties(r)
+		if err != nil {
+			return nil, err
+		}
+		ret := &ModuleSelection{splitList(props["modules"]), splitList(props["profiles"]), props}
+		delete(props, "modules")
+		delete(props, "profiles")
+		return ret, nil
+	}
+	return nil, fmt.Errorf("unknown config format %d", format)
+}
+
+func splitList(value string) []string {
+	var ret []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+// flattenProperties converts nested json object to dot separated property names
+func flattenProperties(prefix string, value interface{}, out map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			if prefix != "" {
+				k = prefix + "." + k
+			}
+			flattenProperties(k, child, out)
+		}
+	case []interface{}:
+		list := make([]string, len(v))
+		for i, e := range v {
+			list[i] = fmt.Sprint(e)
+		}
+		out[prefix] = strings.Join(list, ",")
+	case nil:
+		out[prefix] = ""
+	case float64:
+		out[prefix] = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		out[prefix] = fmt.Sprint(v)
+	}
+}
+
+// parseProperties reads key=value or key: value lines.
+// lines starting with # or ! are comments, export prefix of env files and quotes of value are removed
+func parseProperties(r io.Reader) (map[string]string, error) {
+	ret := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		idx := strings.IndexAny(line, "=:")
+		if idx < 0 {
+			return nil, fmt.Errorf("invalid property at line %d : %s", lineNo, line)
+		}
+
+		key := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		ret[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// Validate returns error if the selection refers modules or profiles which are not registered to implements
+func (r *ModuleSelection) Validate(implements *Implements) error {
+	for _, profile := range r.Profiles {
+		if _, exists := implements.profiles[profile]; !exists {
+			return fmt.Errorf("profile %s has no module", profile)
+		}
+	}
+
+	// module names may have placeholders of properties
+	store, err := implements.withSelectionProperties(r).loadProperties()
+	if err != nil {
+		return err
+	}
+
+	moduleNames, err := expandModuleNames(r.Modules, store.props)
+	if err != nil {
+		return err
+	}
+
+	_, err = implements.resolveModuleNames(moduleNames)
+	return err
+}
+
+// withSelectionProperties returns clone of implements having properties of selection as a property source
+func (r *Implements) withSelectionProperties(selection *ModuleSelection) *Implements {
+	ret := r.Clone()
+	ret.AddPropertySource(MapSource("module selection", selection.Properties))
+	return ret
+}
+
+// NewInjectorFromSelection returns new Injector with modules and properties of selection
+func (r *Implements) NewInjectorFromSelection(selection *ModuleSelection) (Injector, error) {
+	if err := selection.Validate(r); err != nil {
+		return nil, err
+	}
+
+	impls := r.withSelectionProperties(selection)
+
+	moduleNames := selection.Modules
+	if len(selection.Profiles) > 0 {
+		moduleNames = impls.ResolveProfiles(selection.Profiles, selection.Modules)
+	}
+	return impls.NewInjector(moduleNames), nil
+}
+
+// NewInjectorFromConfig loads module selection from the file and returns new Injector.
+// files with .json extension are json format and others are properties format
+func (r *Implements) NewInjectorFromConfig(path string) (Injector, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	format := PropertiesFormat
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		format = JSONFormat
+	}
+
+	selection, err := LoadModuleSelection(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %w", path, err)
+	}
+
+	return r.NewInjectorFromSelection(selection)
+}
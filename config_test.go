@@ -0,0 +1,96 @@
+package di_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+func TestLoadModuleSelectionJSON(t *testing.T) {
+	sel, err := di.LoadModuleSelection(strings.NewReader(`{
+		"modules" : ["V1", "V2"],
+		"properties" : {
+			"db" : { "url" : "localhost", "pool" : { "size" : 10 } },
+			"cache.kind" : "redis"
+		}
+	}`), di.JSONFormat)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(sel.Modules, []string{"V1", "V2"}) {
+		t.Errorf("unexpected modules : %v", sel.Modules)
+	}
+
+	expected := map[string]string{"db.url": "localhost", "db.pool.size": "10", "cache.kind": "redis"}
+	if !reflect.DeepEqual(sel.Properties, expected) {
+		t.Errorf("unexpected properties : %v", sel.Properties)
+	}
+}
+
+func TestLoadModuleSelectionProperties(t *testing.T) {
+	sel, err := di.LoadModuleSelection(strings.NewReader(`
+# comment
+modules = V1, V2
+export DB_URL="localhost"
+cache.kind: redis
+`), di.PropertiesFormat)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(sel.Modules, []string{"V1", "V2"}) {
+		t.Errorf("unexpected modules : %v", sel.Modules)
+	}
+
+	expected := map[string]string{"DB_URL": "localhost", "cache.kind": "redis"}
+	if !reflect.DeepEqual(sel.Properties, expected) {
+		t.Errorf("unexpected properties : %v", sel.Properties)
+	}
+}
+
+func TestNewInjectorFromConfig(t *testing.T) {
+	configured := false
+
+	impls := di.NewImplements()
+	impls.AddImplement("V1", di.BindFunc(func(binder *di.Binder) {
+		configured = true
+		binder.Bind((*Value1)(nil)).When(di.PropertyEquals("cache.kind", "redis")).ToInstance(&ValueImpl{"redis"})
+	}))
+
+	dir := t.TempDir()
+
+	missing := filepath.Join(dir, "missing.json")
+	os.WriteFile(missing, []byte(`{ "modules" : ["V1", "V2"] }`), 0644)
+
+	_, err := impls.NewInjectorFromConfig(missing)
+	if err == nil || err.Error() != "module V2 is not implemented" {
+		t.Errorf("unexpected error : %v", err)
+	}
+
+	if configured {
+		t.Errorf("module configured before validation")
+	}
+
+	path := filepath.Join(dir, "application.properties")
+	os.WriteFile(path, []byte("modules=V1\ncache.kind=redis\n"), 0644)
+
+	injector, err := impls.NewInjectorFromConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if injector.GetProperty("cache.kind") != "redis" {
+		t.Errorf("property not set")
+	}
+
+	if injector.GetInstance((*Value1)(nil)).(Value1).Value() != "redis" {
+		t.Errorf("Value1 not binded")
+	}
+}
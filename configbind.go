@@ -0,0 +1,132 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ConfigValidator is implemented by config struct which validates itself after it is filled by BindConfig
+type ConfigValidator interface {
+	Validate() error
+}
+
+type configTag struct {
+	name         string
+	skip         bool
+	required     bool
+	defaultValue string
+	hasDefault   bool
+}
+
+// parseConfigTag parses prop tag like `prop:"timeout,required"` or `prop:"timeout,default=3s"`.
+// field name in lower case is used if there is no prop tag
+func parseConfigTag(field reflect.StructField) configTag {
+	value, ok := field.Tag.Lookup("prop")
+	if !ok {
+		return configTag{name: strings.ToLower(field.Name)}
+	}
+
+	if value == "-" {
+		return configTag{skip: true}
+	}
+
+	sp := strings.Split(value, ",")
+	ret := configTag{name: sp[0]}
+	if ret.name == "" {
+		ret.name = strings.ToLower(field.Name)
+	}
+
+	for i := 1; i < len(sp); i++ {
+		if sp[i] == "required" {
+			ret.required = true
+		} else if strings.HasPrefix(sp[i], "default=") {
+			// default value may have comma, so it should be the last
+			ret.defaultValue = strings.TrimPrefix(strings.Join(sp[i:], ","), "default=")
+			ret.hasDefault = true
+			break
+		}
+	}
+	return ret
+}
+
+// fillConfig sets exported fields of struct from properties under prefix
+func fillConfig(rv reflect.Value, prefix string, props map[string]string) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+		field := rv.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		tag := parseConfigTag(fieldType)
+		if tag.skip {
+			continue
+		}
+
+		propName := tag.name
+		if prefix != "" {
+			propName = prefix + "." + tag.name
+		}
+
+		if fieldType.Type.Kind() == reflect.Struct {
+			if err := fillConfig(field, propName, props); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if fieldType.Type.Kind() == reflect.Ptr && fieldType.Type.Elem().Kind() == reflect.Struct {
+			nv := reflect.New(fieldType.Type.Elem())
+			if err := fillConfig(nv.Elem(), propName, props); err != nil {
+				return err
+			}
+			field.Set(nv)
+			continue
+		}
+
+		value, ok := props[propName]
+		if !ok {
+			if tag.hasDefault {
+				value = tag.defaultValue
+			} else if tag.required {
+				return fmt.Errorf("property %s is required for %s.%s", propName, t, fieldType.Name)
+			} else {
+				continue
+			}
+		}
+
+		v, err := parsePropertyValue(fieldType.Type, value)
+		if err != nil {
+			return fmt.Errorf("property %s=%q can't be converted to %s for %s.%s : %w", propName, value, fieldType.Type, t, fieldType.Name, err)
+		}
+		field.Set(v)
+	}
+	return nil
+}
+
+// LoadConfig fills the struct pointed by ptrToStruct from properties of injector under prefix.
+// if the struct implements ConfigValidator, Validate is called after it is filled
+func LoadConfig(injector Injector, prefix string, ptrToStruct interface{}) error {
+	rv := reflect.ValueOf(ptrToStruct)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%T is not pointer to struct", ptrToStruct)
+	}
+
+	props := map[string]string{}
+	for _, p := range injector.Properties() {
+		props[p.Name] = p.Value
+	}
+
+	if err := fillConfig(rv.Elem(), prefix, props); err != nil {
+		return err
+	}
+
+	if v, ok := ptrToStruct.(ConfigValidator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid config %T : %w", ptrToStruct, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package di_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+type TLSConfig struct {
+	Enabled bool   `prop:"enabled,default=false"`
+	Cert    string `prop:"cert"`
+}
+
+type HttpConfig struct {
+	Host    string        `prop:"host,required"`
+	Port    int           `prop:"port,default=8080"`
+	Timeout time.Duration `prop:"timeout,default=3s"`
+	TLS     TLSConfig     `prop:"tls"`
+	Ignored string        `prop:"-"`
+}
+
+func (r *HttpConfig) Validate() error {
+	if r.Port <= 0 {
+		return errors.New("port should be positive")
+	}
+	return nil
+}
+
+type httpServer struct {
+	config *HttpConfig
+}
+
+func TestBindConfig(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddBind(func(binder *di.Binder) {
+		di.BindConfig[HttpConfig](binder, "http")
+		di.BindConstructor[*httpServer](binder, func(config *HttpConfig) *httpServer {
+			return &httpServer{config}
+		})
+	})
+	impls.SetProperty("http.host", "localhost")
+	impls.SetProperty("http.tls.enabled", "true")
+	impls.SetProperty("http.tls.cert", "server.pem")
+	impls.SetProperty("http.ignored", "value")
+
+	injector := impls.NewInjector(nil)
+	server := di.GetInstance[*httpServer](injector)
+
+	expected := HttpConfig{"localhost", 8080, 3 * time.Second, TLSConfig{true, "server.pem"}, ""}
+	if *server.config != expected {
+		t.Errorf("unexpected config : %v", *server.config)
+	}
+}
+
+func TestBindConfigRequired(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		} else if r != "property http.host is required for di_test.HttpConfig.Host" {
+			t.Errorf("unexpected panic : %v", r)
+		}
+	}()
+
+	injector := di.CreateInjector(di.BindFunc(func(binder *di.Binder) {
+		di.BindConfig[HttpConfig](binder, "http")
+	}))
+	di.GetInstance[*HttpConfig](injector)
+}
+
+func TestBindConfigValidate(t *testing.T) {
+	config := HttpConfig{}
+
+	injector := di.CreateInjector()
+	injector.SetProperty("http.host", "localhost")
+	injector.SetProperty("http.port", "-1")
+
+	err := di.LoadConfig(injector, "http", &config)
+	if err == nil || err.Error() != "invalid config *di_test.HttpConfig : port should be positive" {
+		t.Errorf("unexpected error : %v", err)
+	}
+}
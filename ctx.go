@@ -0,0 +1,173 @@
+package di
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CreationError is returned when creating injector is failed or aborted.
+// it reports instances which are not completed when the creation is stopped
+type CreationError struct {
+	Err error
+
+	// Requested are instances requested to create but not completed
+	Requested     []*TraceInfo
+	LastRequested *TraceInfo
+	LastCreated   *TraceInfo
+	Longest       *TraceInfo
+}
+
+func (r *CreationError) Error() string {
+	builder := strings.Builder{}
+	builder.WriteString("Not completed : \n\t\t")
+	for _, v := range r.Requested {
+		builder.WriteString(v.String())
+		builder.WriteString("\n\t\t")
+	}
+	return fmt.Sprintf("%s\n\tLast %s\n\tLast %s\n\tlongest time to %s\n\t%s", r.Err, r.LastRequested, r.LastCreated, r.Longest, builder.String())
+}
+
+func (r *CreationError) Unwrap() error {
+	return r.Err
+}
+
+// creationTracer records progress of injector creation to report CreationError
+type creationTracer struct {
+	lock          sync.Mutex
+	lastRequested *TraceInfo
+	lastCreated   *TraceInfo
+	longest       *TraceInfo
+	requested     map[reflect.Type]*TraceInfo
+	next          TraceCallback
+}
+
+func newCreationTracer(next TraceCallback) *creationTracer {
+	return &creationTracer{requested: map[reflect.Type]*TraceInfo{}, next: next}
+}
+
+func (r *creationTracer) trace(info *TraceInfo) {
+	r.lock.Lock()
+	if info.TraceType == InstanceWillBeCreated {
+		r.lastRequested = info
+		r.requested[info.RequestedType] = info
+	} else if info.TraceType == InstanceCreated {
+		delete(r.requested, info.RequestedType)
+		r.lastCreated = info
+		if r.longest == nil || info.ElapsedTime > r.longest.ElapsedTime {
+			r.longest = info
+		}
+	}
+	r.lock.Unlock()
+
+	if r.next != nil {
+		r.next(info)
+	}
+}
+
+func (r *creationTracer) error(err error) *CreationError {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := &CreationError{
+		Err:           err,
+		LastRequested: r.lastRequested,
+		LastCreated:   r.lastCreated,
+		Longest:       r.longest,
+	}
+	for _, v := range r.requested {
+		ret.Requested = append(ret.Requested, v)
+	}
+	sort.Slice(ret.Requested, func(i, j int) bool {
+		return ret.Requested[i].RequestedType.String() < ret.Requested[j].RequestedType.String()
+	})
+	return ret
+}
+
+// panicError converts recovered value to error
+func panicError(p interface{}) error {
+	if err, ok := p.(error); ok {
+		return err
+	}
+	return errors.New(fmt.Sprint(p))
+}
+
+func isAborted(p interface{}) bool {
+	err, ok := p.(error)
+	return ok && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded))
+}
+
+// contextOf returns context of injector passed to providers
+func contextOf(injector Injector) context.Context {
+	if ic, ok := injector.(*injectorContext); ok && ic.ctx != nil {
+		return ic.ctx
+	}
+	return context.Background()
+}
+
+func (r *injectorContext) ctxDone() <-chan struct{} {
+	if r.ctx == nil {
+		return nil
+	}
+	return r.ctx.Done()
+}
+
+func (r *injectorContext) panicOnAbort(t reflect.Type) {
+	if r.ctx == nil {
+		return
+	}
+	if err := r.ctx.Err(); err != nil {
+		panic(fmt.Errorf("creation of %s is aborted : %w", t, err))
+	}
+}
+
+// ToProviderCtx binds type to the provider which observes context of GetInstanceCtx or NewInjectorContext.
+// error returned by provider is returned from GetInstanceCtx
+func (b *Binding) ToProviderCtx(provider func(ctx context.Context, injector Injector) (interface{}, error)) *Binding {
+	return b.ToProvider(func(injector Injector) interface{} {
+		ret, err := provider(contextOf(injector), injector)
+		if err != nil {
+			panic(fmt.Errorf("can't create %s : %w", b.tpe, err))
+		}
+		return ret
+	})
+}
+
+func (r *injectorImpl) GetInstanceCtx(ctx context.Context, ptrToType interface{}) (ret interface{}, err error) {
+	context := newInjectorContext(r)
+	return context.GetInstanceCtx(ctx, ptrToType)
+}
+
+func (r *injectorContext) GetInstanceCtx(ctx context.Context, ptrToType interface{}) (ret interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			ret = nil
+			err = panicError(p)
+		}
+	}()
+
+	context := r.clone()
+	context.ctx = ctx
+	return context.GetInstance(ptrToType), nil
+}
+
+// NewInjectorContext returns new Injector from implements with enabled modulenames.
+// creation is aborted if ctx is done and it returns *CreationError instead of panic
+func (r *Implements) NewInjectorContext(ctx context.Context, moduleNames []string, options ...InjectorOption) (ret Injector, err error) {
+	tracer := newCreationTracer(nil)
+	defer func() {
+		if p := recover(); p != nil {
+			ret = nil
+			err = tracer.error(panicError(p))
+		}
+	}()
+	injector := r.Clone().newInjector(ctx, moduleNames, tracer.trace, options...)
+
+	// tracer is used only while creating the injector, so instances created later are not traced
+	injector.traceCallback = nil
+	return injector, nil
+}
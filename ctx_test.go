@@ -0,0 +1,97 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+type slowStore struct{}
+
+func TestNewInjectorContextTimeout(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddImplement("Store", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*slowStore](binder).ToProviderCtx(func(ctx context.Context, injector di.Injector) (*slowStore, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).AsEagerSingleton()
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	injector, err := impls.NewInjectorContext(ctx, []string{"Store"})
+	if injector != nil {
+		t.Error("injector is returned")
+	}
+
+	var cerr *di.CreationError
+	if !errors.As(err, &cerr) {
+		t.Fatalf("unexpected error : %v", err)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("cause is not deadline : %v", cerr.Err)
+	}
+
+	if len(cerr.Requested) != 1 || cerr.Requested[0].RequestedType.String() != "*di_test.slowStore" {
+		t.Errorf("requested is not reported : %v", cerr.Requested)
+	}
+}
+
+func TestGetInstanceCtxCancel(t *testing.T) {
+	first := true
+	impls := di.NewImplements()
+	impls.AddImplement("Store", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*slowStore](binder).ToProviderCtx(func(ctx context.Context, injector di.Injector) (*slowStore, error) {
+			if first {
+				first = false
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return &slowStore{}, nil
+		})
+	}))
+
+	injector, err := impls.NewInjectorContext(context.Background(), []string{"Store"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err = di.GetInstanceCtx[*slowStore](ctx, injector)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error : %v", err)
+	}
+
+	// aborted singleton is created again
+	store, err := di.GetInstanceCtx[*slowStore](context.Background(), injector)
+	if err != nil || store == nil {
+		t.Errorf("store is not created : %v", err)
+	}
+}
+
+func TestGetInstanceCtxError(t *testing.T) {
+	errNotReady := errors.New("not ready")
+
+	impls := di.NewImplements()
+	impls.AddImplement("Store", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*slowStore](binder).ToProviderCtx(func(ctx context.Context, injector di.Injector) (*slowStore, error) {
+			return nil, errNotReady
+		})
+	}))
+
+	injector := impls.NewInjector([]string{"Store"})
+	_, err := di.GetInstanceCtx[*slowStore](context.Background(), injector)
+	if !errors.Is(err, errNotReady) {
+		t.Errorf("unexpected error : %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// singletonBuild is a singleton being created by an injectorContext
+type singletonBuild struct {
+	owner  *injectorContext
+	done   chan struct{}
+	failed bool
+}
+
+// buildRegistry tracks singletons in progress across goroutines.
+// waiting is the singleton each context is waiting for which is being created by other context
+type buildRegistry struct {
+	lock     sync.Mutex
+	building map[*Binding]*singletonBuild
+	waiting  map[*injectorContext]*Binding
+}
+
+var singletonBuilds = &buildRegistry{
+	building: map[*Binding]*singletonBuild{},
+	waiting:  map[*injectorContext]*Binding{},
+}
+
+// buildSingleton calls create while p is registered as being created by r.
+// if other context is creating p, it waits until the creation is completed
+// and panics if waiting makes a cycle or exceeds singletonWaitTimeout
+func (r *injectorContext) buildSingleton(p *Binding, create func()) {
+	if p.created.Load() || !singletonBuilds.acquire(r, p) {
+		create()
+		return
+	}
+
+	completed := false
+	defer func() {
+		singletonBuilds.release(p, completed)
+	}()
+	create()
+	completed = true
+}
+
+// acquire returns true if r becomes owner of p.
+// it returns false if r already owns p or other owner completed p
+func (reg *buildRegistry) acquire(r *injectorContext, p *Binding) bool {
+	reg.lock.Lock()
+
+	b := reg.building[p]
+	if b == nil {
+		reg.building[p] = &singletonBuild{owner: r, done: make(chan struct{})}
+		reg.lock.Unlock()
+		return true
+	}
+
+	if b.owner == r {
+		reg.lock.Unlock()
+		return false
+	}
+
+	if chain := reg.cycle(r, p); chain != nil {
+		reg.lock.Unlock()
+		panic("dependency cycle across goroutines : \n" + formatChain(chain))
+	}
+
+	reg.waiting[r] = p
+	reg.lock.Unlock()
+
+	var timeoutC <-chan time.Time
+	if timeout := r.injector.singletonWaitTimeout; timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		timeoutC = timer.C
+	}
+
+	timedOut := false
+	aborted := false
+	select {
+	case <-b.done:
+	case <-timeoutC:
+		timedOut = true
+	case <-r.ctxDone():
+		aborted = true
+	}
+
+	reg.lock.Lock()
+	delete(reg.waiting, r)
+	if aborted {
+		reg.lock.Unlock()
+		panic(fmt.Errorf("creation of %s is aborted : %w", p.tpe, r.ctx.Err()))
+	}
+	var chain []reflect.Type
+	if timedOut {
+		chain = append(r.stackOf(), reg.waitChain(p)...)
+	}
+	reg.lock.Unlock()
+
+	if timedOut {
+		panic(fmt.Sprintf("timeout waiting for %s created by another goroutine : %s\n%s", p.tpe, r.injector.singletonWaitTimeout, formatChain(chain)))
+	}
+
+	if b.failed {
+		panic(fmt.Sprintf("creation of %s failed in another goroutine", p.tpe))
+	}
+	return false
+}
+
+func (reg *buildRegistry) release(p *Binding, completed bool) {
+	reg.lock.Lock()
+	defer reg.lock.Unlock()
+
+	if b := reg.building[p]; b != nil {
+		b.failed = !completed
+		delete(reg.building, p)
+		close(b.done)
+	}
+}
+
+// cycle returns combined chain of types if r waiting for p makes a wait cycle
+func (reg *buildRegistry) cycle(r *injectorContext, p *Binding) []reflect.Type {
+	var owners []*injectorContext
+	var waitFor []*Binding
+
+	visited := map[*injectorContext]bool{}
+	cur := p
+	for {
+		b := reg.building[cur]
+		if b == nil || visited[b.owner] {
+			return nil
+		}
+		visited[b.owner] = true
+
+		if b.owner == r {
+			break
+		}
+
+		next := reg.waiting[b.owner]
+		if next == nil {
+			return nil
+		}
+		owners = append(owners, b.owner)
+		waitFor = append(waitFor, next)
+		cur = next
+	}
+
+	// r owns the last waited singleton, so the chain starts from it in r's stack
+	start := p
+	if len(waitFor) > 0 {
+		start = waitFor[len(waitFor)-1]
+	}
+
+	chain := stackFrom(r.stackOf(), start.tpe)
+	chain = append(chain, p.tpe)
+	requested := p
+	for i, owner := range owners {
+		chain = append(chain, stackAfter(owner.stackOf(), requested.tpe)...)
+		chain = append(chain, waitFor[i].tpe)
+		requested = waitFor[i]
+	}
+	return chain
+}
+
+// waitChain returns types being created by the owner of p and the singletons it waits for
+func (reg *buildRegistry) waitChain(p *Binding) []reflect.Type {
+	chain := []reflect.Type{p.tpe}
+
+	visited := map[*injectorContext]bool{}
+	cur := p
+	for {
+		b := reg.building[cur]
+		if b == nil || visited[b.owner] {
+			return chain
+		}
+		visited[b.owner] = true
+
+		chain = append(chain, stackAfter(b.owner.stackOf(), cur.tpe)...)
+		next := reg.waiting[b.owner]
+		if next == nil {
+			return chain
+		}
+		chain = append(chain, next.tpe)
+		cur = next
+	}
+}
+
+func (r *injectorContext) stackOf() []reflect.Type {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]reflect.Type(nil), r.stack...)
+}
+
+func stackFrom(stack []reflect.Type, t reflect.Type) []reflect.Type {
+	for i, s := range stack {
+		if s == t {
+			return stack[i:]
+		}
+	}
+	return stack
+}
+
+func stackAfter(stack []reflect.Type, t reflect.Type) []reflect.Type {
+	for i, s := range stack {
+		if s == t {
+			return stack[i+1:]
+		}
+	}
+	return stack
+}
+
+func formatChain(chain []reflect.Type) string {
+	strs := make([]string, len(chain))
+	for i, t := range chain {
+		strs[i] = t.String()
+	}
+	return strings.Join(strs, "\n  -> ")
+}
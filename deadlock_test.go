@@ -0,0 +1,107 @@
+package di_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+type serviceX struct{}
+type serviceY struct{}
+
+func TestCrossGoroutineCycle(t *testing.T) {
+	startedX := make(chan struct{})
+	startedY := make(chan struct{})
+
+	impls := di.NewImplements()
+	impls.AddImplement("Services", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*serviceX](binder).ToProvider(func(injector di.Injector) *serviceX {
+			close(startedX)
+			<-startedY
+			di.GetInstance[*serviceY](injector)
+			return &serviceX{}
+		})
+
+		di.Bind[*serviceY](binder).ToProvider(func(injector di.Injector) *serviceY {
+			close(startedY)
+			<-startedX
+			di.GetInstance[*serviceX](injector)
+			return &serviceY{}
+		})
+	}))
+
+	injector := impls.NewInjector([]string{"Services"})
+
+	errs := make([]string, 2)
+	wg := sync.WaitGroup{}
+	get := func(i int, f func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				errs[i] = fmt.Sprint(recover())
+			}()
+			f()
+		}()
+	}
+
+	get(0, func() { di.GetInstance[*serviceX](injector) })
+	get(1, func() { di.GetInstance[*serviceY](injector) })
+
+	finished := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock is not detected")
+	}
+
+	cycle := ""
+	for _, e := range errs {
+		if strings.HasPrefix(e, "dependency cycle across goroutines") {
+			cycle = e
+		} else if !strings.Contains(e, "failed in another goroutine") {
+			t.Errorf("unexpected error : %s", e)
+		}
+	}
+
+	if !strings.Contains(cycle, "*di_test.serviceX") || !strings.Contains(cycle, "*di_test.serviceY") {
+		t.Errorf("chain is not reported : %s", cycle)
+	}
+}
+
+func TestSingletonWaitTimeout(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	impls := di.NewImplements()
+	impls.AddImplement("Services", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*serviceX](binder).ToProvider(func(injector di.Injector) *serviceX {
+			close(started)
+			<-release
+			return &serviceX{}
+		})
+	}))
+
+	injector := impls.NewInjector([]string{"Services"}, di.WithSingletonWaitTimeout(50*time.Millisecond))
+	defer close(release)
+
+	go di.GetInstance[*serviceX](injector)
+	<-started
+
+	defer func() {
+		r := recover()
+		if r == nil || !strings.HasPrefix(fmt.Sprint(r), "timeout waiting for *di_test.serviceX") {
+			t.Errorf("unexpected : %v", r)
+		}
+	}()
+	di.GetInstance[*serviceX](injector)
+}
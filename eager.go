@@ -0,0 +1,114 @@
+package di
+
+import (
+	"reflect"
+	"sort"
+	"sync/atomic"
+
+	"github.com/csgura/fp/reflectfp"
+)
+
+// bindingSeq gives registration order to bindings
+var bindingSeq atomic.Uint64
+
+// argumentDependencies returns binding keys which are injected to the argument or field type.
+// lazy and Provider arguments are not dependencies because they don't create instances on injection
+func argumentDependencies(t reflect.Type) []reflect.Type {
+	if _, ok := reflect.New(t).Interface().(propertyParam); ok {
+		return nil
+	}
+	if _, ok := reflect.New(t).Interface().(providerParam); ok {
+		return nil
+	}
+	if reflectfp.MatchLazyEval(t).IsDefined() {
+		return nil
+	}
+	if optType, ok := reflectfp.MatchOption(t).Unapply(); ok {
+		return []reflect.Type{bindKeyOf(optType)}
+	}
+
+	if isInStruct(t) {
+		var ret []reflect.Type
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.IsExported() && !f.Anonymous && f.Tag.Get("di") != "-" && hasInjectTag(f.Tag).prop == "" {
+				ret = append(ret, argumentDependencies(f.Type)...)
+			}
+		}
+		return ret
+	}
+	return []reflect.Type{bindKeyOf(t)}
+}
+
+// constructorDependencies returns binding keys which are injected to arguments of the function
+func constructorDependencies(function interface{}) []reflect.Type {
+	ftype := reflect.TypeOf(function)
+	if ftype == nil || ftype.Kind() != reflect.Func {
+		return nil
+	}
+
+	var ret []reflect.Type
+	for i := 0; i < ftype.NumIn(); i++ {
+		ret = append(ret, argumentDependencies(ftype.In(i))...)
+	}
+	return ret
+}
+
+// eagerDependencies returns eager bindings which the binding depends on directly or through other bindings
+func (b *Binder) eagerDependencies(binding *Binding) []*Binding {
+	var ret []*Binding
+	visited := map[*Binding]bool{binding: true}
+
+	var visit func(p *Binding)
+	visit = func(p *Binding) {
+		for _, t := range p.dependencies {
+			dep := b.providers[t]
+			if dep == nil || visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			if dep.isEager {
+				ret = append(ret, dep)
+			}
+			visit(dep)
+		}
+	}
+	visit(binding)
+	return ret
+}
+
+// eagerOrder returns types of eager singletons in creation order.
+// eager singletons are in registration order, but eager singletons which one depends on come before it
+func (b *Binder) eagerOrder() []reflect.Type {
+	var eagers []*Binding
+	for _, p := range b.providers {
+		if p.isEager {
+			eagers = append(eagers, p)
+		}
+	}
+	sort.Slice(eagers, func(i, j int) bool {
+		return eagers[i].seq < eagers[j].seq
+	})
+
+	done := map[*Binding]bool{}
+	ret := make([]reflect.Type, 0, len(eagers))
+
+	// emit eager dependencies before the binding.
+	// on dependency cycle, done is already set, so it will be reported when the instance is created
+	var emit func(e *Binding)
+	emit = func(e *Binding) {
+		if done[e] {
+			return
+		}
+		done[e] = true
+		for _, d := range b.eagerDependencies(e) {
+			emit(d)
+		}
+		ret = append(ret, e.tpe)
+	}
+
+	for _, e := range eagers {
+		emit(e)
+	}
+	return ret
+}
@@ -0,0 +1,55 @@
+package di_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+type migration struct{}
+type discovery struct{}
+type metricsReporter struct{}
+type schema struct{}
+
+func TestEagerOrder(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		var created []string
+
+		impls := di.NewImplements()
+		impls.AddImplement("Discovery", di.BindFunc(func(binder *di.Binder) {
+			// discovery depends on migration through schema
+			di.Bind[*discovery](binder).ToConstructor(func(s *schema) *discovery {
+				created = append(created, "discovery")
+				return &discovery{}
+			}).AsEagerSingleton()
+
+			di.Bind[*metricsReporter](binder).ToConstructor(func() *metricsReporter {
+				created = append(created, "metrics")
+				return &metricsReporter{}
+			}).AsEagerSingleton()
+		}))
+
+		impls.AddImplement("Migration", di.BindFunc(func(binder *di.Binder) {
+			di.Bind[*schema](binder).ToConstructor(func(m *migration) *schema {
+				return &schema{}
+			})
+
+			di.Bind[*migration](binder).ToConstructor(func() *migration {
+				created = append(created, "migration")
+				return &migration{}
+			}).AsEagerSingleton()
+		}))
+
+		injector := impls.NewInjector([]string{"Discovery", "Migration"})
+
+		if !reflect.DeepEqual(created, []string{"migration", "discovery", "metrics"}) {
+			t.Fatalf("unexpected creation order : %v", created)
+		}
+
+		expected := []reflect.Type{reflect.TypeOf(&migration{}), reflect.TypeOf(&discovery{}), reflect.TypeOf(&metricsReporter{})}
+		if !reflect.DeepEqual(injector.EagerSingletons(), expected) {
+			t.Fatalf("unexpected eager singletons : %v", injector.EagerSingletons())
+		}
+	}
+}
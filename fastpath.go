@@ -0,0 +1,34 @@
+package di
+
+// readySingleton is singleton instance which is created and decorated
+type readySingleton struct {
+	instance interface{}
+
+	// gen is refresh generation of the instance
+	gen uint64
+}
+
+// readyInstance returns created singleton without lock and allocation.
+// it returns false if the singleton is not created yet, so it should be created with injectorContext
+func (b *Binding) readyInstance() (interface{}, bool) {
+	ready := b.ready.Load()
+	if ready == nil || ready.gen != b.refreshGen.Load() {
+		return nil, false
+	}
+
+	if b.counters != nil {
+		b.counters.requests.Add(1)
+	}
+	return ready.instance, true
+}
+
+// setReady makes the singleton returned by fast path unless it is refreshed while it is created
+func (b *Binding) setReady(instance interface{}) {
+	gen := b.builtGen.Load()
+	if gen != b.refreshGen.Load() {
+		return
+	}
+	if ready := b.ready.Load(); ready == nil || ready.gen != gen {
+		b.ready.Store(&readySingleton{instance, gen})
+	}
+}
@@ -0,0 +1,59 @@
+package di_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+func TestSingletonFastPath(t *testing.T) {
+	injector := benchInjector()
+	first := di.GetInstance[benchCache](injector)
+	before := requestsOf(injector, "*di_test.benchCache")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if di.GetInstance[benchCache](injector) != first {
+			t.Error("another instance is returned")
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("allocs = %f", allocs)
+	}
+
+	// fast path still counts requests
+	if requests := requestsOf(injector, "*di_test.benchCache") - before; requests != 101 {
+		t.Errorf("requests = %d", requests)
+	}
+}
+
+func requestsOf(injector di.Injector, typeName string) uint64 {
+	for _, s := range injector.Stats() {
+		if s.Type.String() == typeName {
+			return s.Requests
+		}
+	}
+	return 0
+}
+
+func TestSingletonFastPathAfterCreationTrace(t *testing.T) {
+	injector, err := benchImplements().NewInjectorContext(context.Background(), []string{"Bench"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	withTimeout := benchImplements().NewInjectorWithTimeout([]string{"Bench"}, time.Second)
+
+	for _, injector := range []di.Injector{injector, withTimeout} {
+		first := di.GetInstance[benchCache](injector)
+		allocs := testing.AllocsPerRun(100, func() {
+			if di.GetInstance[benchCache](injector) != first {
+				t.Error("another instance is returned")
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("allocs = %f", allocs)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package di
 
 import (
+	"context"
 	"reflect"
 
 	"github.com/csgura/fp"
@@ -20,13 +21,23 @@ func GetInstance[T any](injector Injector) T {
 		return t
 	}
 
-	ret := injector.GetInstance(&t)
+	ret := injector.GetInstance((*T)(nil))
 	if ret != nil {
 		return ret.(T)
 	}
 	return t
 }
 
+// GetInstanceCtx returns instance of T. creation is aborted if ctx is done
+func GetInstanceCtx[T any](ctx context.Context, injector Injector) (T, error) {
+	var t T
+	ret, err := injector.GetInstanceCtx(ctx, TypeOf[T]())
+	if err != nil || ret == nil {
+		return t, err
+	}
+	return ret.(T), nil
+}
+
 func GetInstanceOpt[T any](injector Injector) fp.Option[T] {
 	var t T
 	if reflect.ValueOf(t).Kind() == reflect.Ptr {
@@ -120,6 +131,13 @@ func (b BindingTP[T]) ToProvider(provider func(injector Injector) T) BindingTP[T
 	return b
 }
 
+func (b BindingTP[T]) ToProviderCtx(provider func(ctx context.Context, injector Injector) (T, error)) BindingTP[T] {
+	b.binding.ToProviderCtx(func(ctx context.Context, injector Injector) (interface{}, error) {
+		return provider(ctx, injector)
+	})
+	return b
+}
+
 func (b BindingTP[T]) ToInstance(singleton T) BindingTP[T] {
 	b.binding.ToInstance(singleton)
 	return b
@@ -130,11 +148,21 @@ func (b BindingTP[T]) ToConstructor(constructor interface{}) BindingTP[T] {
 	return b
 }
 
+func (b BindingTP[T]) When(condition Condition) BindingTP[T] {
+	b.binding.When(condition)
+	return b
+}
+
 func (b BindingTP[T]) AsEagerSingleton() BindingTP[T] {
 	b.binding.AsEagerSingleton()
 	return b
 }
 
+func (b BindingTP[T]) AsNonSingleton() BindingTP[T] {
+	b.binding.AsNonSingleton()
+	return b
+}
+
 func Bind[T any](binder *Binder) BindingTP[T] {
 	var t T
 	if reflect.ValueOf(t).Kind() == reflect.Ptr {
@@ -167,6 +195,101 @@ func TypeOf[T any]() interface{} {
 	if reflect.ValueOf(t).Kind() == reflect.Ptr {
 		return t
 	} else {
-		return &t
+		// typed nil pointer is enough for binding key and it doesn't allocate
+		return (*T)(nil)
+	}
+}
+
+// PropertyKey names a property injected to Prop.
+// if it also has PropertyDefault() string method, the default is used when the property is not set
+type PropertyKey interface {
+	PropertyName() string
+}
+
+// Prop is an argument type of constructor which is injected from property named by K
+type Prop[T any, K PropertyKey] struct {
+	value T
+}
+
+// Get returns the property value
+func (r Prop[T, K]) Get() T {
+	return r.value
+}
+
+func (r *Prop[T, K]) propertyTag() injectTag {
+	var k K
+	ret := injectTag{prop: k.PropertyName()}
+	if d, ok := any(k).(interface{ PropertyDefault() string }); ok {
+		ret.defaultValue = d.PropertyDefault()
+		ret.hasDefault = true
+	}
+	return ret
+}
+
+func (r *Prop[T, K]) valueType() reflect.Type {
+	return reflect.TypeOf(&r.value).Elem()
+}
+
+func (r *Prop[T, K]) setValue(v reflect.Value) {
+	r.value = v.Interface().(T)
+}
+
+// BindConfig binds *T to singleton which is filled from properties under prefix.
+// fields are named by prop tag like `prop:"timeout,default=3s"` and nested structs extend prefix
+func BindConfig[T any](binder *Binder, prefix string) *Binding {
+	return binder.BindProvider((*T)(nil), func(injector Injector) interface{} {
+		ret := new(T)
+		if err := LoadConfig(injector, prefix, ret); err != nil {
+			panic(err.Error())
+		}
+		return ret
+	})
+}
+
+// Provider returns current instance of T whenever Get is called.
+// it can be injected to constructor arguments and struct fields
+type Provider[T any] struct {
+	getter func() interface{}
+}
+
+// Get returns instance of T or zero value if T is not binded
+func (r Provider[T]) Get() T {
+	var zero T
+	if r.getter == nil {
+		return zero
 	}
+	if ret := r.getter(); ret != nil {
+		return ret.(T)
+	}
+	return zero
+}
+
+func (r *Provider[T]) bindType() reflect.Type {
+	return reflect.TypeOf(TypeOf[T]())
+}
+
+func (r *Provider[T]) setGetter(getter func() interface{}) {
+	r.getter = getter
+}
+
+// GetProvider returns Provider of T
+func GetProvider[T any](injector Injector) Provider[T] {
+	return Provider[T]{func() interface{} {
+		return injector.GetInstance(TypeOf[T]())
+	}}
+}
+
+func (b BindingTP[T]) WithRetry(policy RetryPolicy) BindingTP[T] {
+	b.binding.WithRetry(policy)
+	return b
+}
+
+func (b BindingTP[T]) Refreshable(patterns ...string) BindingTP[T] {
+	b.binding.Refreshable(patterns...)
+	return b
+}
+
+// As binds the constructor preceding it in Provide arguments to interface T too
+func As[T any]() ProvideOption {
+	return asOption{reflect.TypeOf(TypeOf[T]())}
 }
@@ -1,10 +1,11 @@
 package di
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"reflect"
 	"strings"
-	"sync"
 	"time"
 )
 
@@ -16,15 +17,27 @@ func (bf BindFunc) Configure(binder *Binder) {
 	bf(binder)
 }
 
+// RequiringModule is a module that requires other named modules of Implements.
+// required modules are configured automatically even if they are not enabled
+type RequiringModule interface {
+	AbstractModule
+	Requires() []string
+}
+
 // Implements is registry of AbstractModule
 type Implements struct {
 	implements      map[string]AbstractModule
 	anonymousModule []AbstractModule
+	props           map[string]string
+	propSources     []PropertySource
+	profiles        map[string][]string
+	lastAdded       string
 }
 
 // AddImplement adds named abstract module to Implements
 func (r *Implements) AddImplement(name string, impl AbstractModule) *Implements {
 	r.implements[name] = impl
+	r.lastAdded = name
 	return r
 }
 
@@ -46,6 +59,18 @@ func (r *Implements) AddImplements(impl *Implements) *Implements {
 			r.implements[k] = v
 		}
 	}
+	for profile, names := range impl.profiles {
+		for _, name := range names {
+			r.addToProfile(profile, name)
+		}
+	}
+	return r
+}
+
+// SetProperty sets property which is used to evaluate conditions of bindings
+// and it is also set to created injector
+func (r *Implements) SetProperty(propName string, value string) *Implements {
+	r.props[propName] = value
 	return r
 }
 
@@ -62,16 +87,104 @@ func (r *Implements) Clone() *Implements {
 	for _, nonamed := range r.anonymousModule {
 		ret.anonymousModule = append(ret.anonymousModule, nonamed)
 	}
+	for k, v := range r.props {
+		ret.props[k] = v
+	}
+	ret.propSources = append(ret.propSources, r.propSources...)
 	return ret
 }
 
+// resolveModuleNames returns enabled module names with required modules.
+// required modules come before the module requiring them and each name appears only once
+func (r *Implements) resolveModuleNames(moduleNames []string) ([]string, error) {
+	var ret []string
+	resolved := map[string]bool{}
+	var path []string
+
+	var visit func(name string, requiredBy string) error
+	visit = func(name string, requiredBy string) error {
+		if resolved[name] {
+			return nil
+		}
+
+		for i, p := range path {
+			if p == name {
+				return fmt.Errorf("module dependency cycle : %s", strings.Join(append(path[i:], name), " -> "))
+			}
+		}
+
+		module := r.implements[name]
+		if module == nil {
+			if requiredBy != "" {
+				return fmt.Errorf("module %s is not implemented (required by %s)", name, requiredBy)
+			}
+			return fmt.Errorf("module %s is not implemented", name)
+		}
+
+		if rm, ok := module.(RequiringModule); ok {
+			path = append(path, name)
+			for _, req := range rm.Requires() {
+				if err := visit(req, name); err != nil {
+					return err
+				}
+			}
+			path = path[:len(path)-1]
+		}
+
+		resolved[name] = true
+		ret = append(ret, name)
+		return nil
+	}
+
+	for _, name := range moduleNames {
+		if err := visit(name, ""); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
 // NewInjectorWithTrace creates injector and call callback function when instances are created
-func (r *Implements) NewInjectorWithTrace(moduleNames []string, traceCallback TraceCallback) Injector {
+func (r *Implements) NewInjectorWithTrace(moduleNames []string, traceCallback TraceCallback, options ...InjectorOption) Injector {
+	return r.newInjector(nil, moduleNames, traceCallback, options...)
+}
+
+func (r *Implements) newInjector(ctx context.Context, moduleNames []string, traceCallback TraceCallback, options ...InjectorOption) *injectorImpl {
+	opts := newInjectorOptions(options)
+
+	if opts.logger != nil {
+		start := time.Now()
+		defer func() {
+			if p := recover(); p != nil {
+				opts.logger.Error("injector creation failed", slog.Any("modules", moduleNames), slog.Any("error", panicError(p)))
+				panic(p)
+			}
+			opts.logger.Info("injector created", slog.Any("modules", moduleNames), slog.Duration("elapsed", time.Since(start)))
+		}()
+	}
+
+	store, err := r.loadProperties()
+	if err != nil {
+		panic(err.Error())
+	}
+	props := store.props
+
+	moduleNames, err = expandModuleNames(moduleNames, props)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	moduleNames, err = r.resolveModuleNames(moduleNames)
+	if err != nil {
+		panic(err.Error())
+	}
+
 	binder := newBinder()
+	binder.profiles = opts.profiles
 
 	binder.ignoreDuplicate = true
 	for i := len(r.anonymousModule) - 1; i >= 0; i-- {
-		r.anonymousModule[i].Configure(binder)
+		binder.Install(r.anonymousModule[i])
 	}
 
 	binder.ignoreDuplicate = false
@@ -86,7 +199,7 @@ func (r *Implements) NewInjectorWithTrace(moduleNames []string, traceCallback Tr
 					continue
 				}
 			}
-			r.implements[m].Configure(binder)
+			binder.install(m, module)
 		} else {
 			panic(fmt.Sprintf("module %s is not implemented", m))
 		}
@@ -96,12 +209,14 @@ func (r *Implements) NewInjectorWithTrace(moduleNames []string, traceCallback Tr
 		for _, name := range moduleNames {
 			module := r.implements[name]
 			if module != nil {
-				overBinder := newBinder()
+				overBinder := binder.newChildBinder()
 
 				if overriden, ok := module.(*orverriden); ok {
+					overBinder.modules.enter(name, nil)
 					for _, m := range overriden.modules {
-						m.Configure(overBinder)
+						overBinder.Install(m)
 					}
+					overBinder.modules.leave()
 				}
 
 				binder.merge(overBinder, false)
@@ -110,9 +225,9 @@ func (r *Implements) NewInjectorWithTrace(moduleNames []string, traceCallback Tr
 
 	}
 
-	binder.mergeFallbacks()
+	binder.resolveConditionals(props)
 
-	injector := &injectorImpl{binder, make(map[string]string), traceCallback}
+	injector := &injectorImpl{binder: binder, props: store, traceCallback: traceCallback, singletonWaitTimeout: opts.singletonWaitTimeout, logger: opts.logger}
 
 	var injectorIntf *Injector
 	injectorType := reflect.TypeOf(injectorIntf)
@@ -124,73 +239,64 @@ func (r *Implements) NewInjectorWithTrace(moduleNames []string, traceCallback Tr
 		isSingleton: true,
 	}
 
-	context := injectorContext{injector, make(map[reflect.Type]bool), nil, nil, traceCallback, sync.Mutex{}}
+	injector.initStats()
+
+	context := newInjectorContext(injector)
 	context.callDecorators(injectorType)
 
-	for t := range binder.providers {
-		if binder.providers[t].isEager {
-			injector.getInstanceByType(t)
-			//fmt.Printf("eager singleton %v -> %v\n", t, ret)
-		}
-	}
+	injector.watchRefreshables()
+
+	injector.eagerOrder = binder.eagerOrder()
+	injector.createEagerSingletons(ctx, opts.parallelEager)
 	return injector
 }
 
 // NewInjector returns new Injector from implements with enabled modulenames
-func (r *Implements) NewInjector(moduleNames []string) Injector {
-	return r.Clone().NewInjectorWithTrace(moduleNames, func(info *TraceInfo) {
-
-	})
+func (r *Implements) NewInjector(moduleNames []string, options ...InjectorOption) Injector {
+	return r.Clone().NewInjectorWithTrace(moduleNames, nil, options...)
 }
 
 // NewInjectorWithTimeout returns new Injector from implements with enabled modulenames
-// and it checks timeout
+// and it checks timeout. pending creation is aborted after timeout
 func (r *Implements) NewInjectorWithTimeout(moduleNames []string, timeout time.Duration) Injector {
-	ch := make(chan Injector)
+	type result struct {
+		injector Injector
+		failed   interface{}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	var lastRequested *TraceInfo
-	var lastCreated *TraceInfo
-	var longest *TraceInfo
-	requested := map[reflect.Type]*TraceInfo{}
-	lock := sync.Mutex{}
+	ch := make(chan result, 1)
+	tracer := newCreationTracer(nil)
 
 	go func() {
-		ret := r.NewInjectorWithTrace(moduleNames, func(info *TraceInfo) {
-			lock.Lock()
-			defer lock.Unlock()
-			if info.TraceType == InstanceWillBeCreated {
-				lastRequested = info
-				requested[info.RequestedType] = info
-			} else if info.TraceType == InstanceCreated {
-				delete(requested, info.RequestedType)
-				lastCreated = info
-				if longest == nil || info.ElapsedTime > longest.ElapsedTime {
-					longest = info
-				}
+		defer func() {
+			if p := recover(); p != nil {
+				ch <- result{failed: p}
 			}
-		})
-		ch <- ret
+		}()
+		injector := r.newInjector(ctx, moduleNames, tracer.trace)
+		injector.traceCallback = nil
+		ch <- result{injector: injector}
 	}()
 
 	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
 	select {
-	case injector := <-ch:
-		return injector
-	case <-timer.C:
-		builder := strings.Builder{}
-		builder.WriteString("Not completed : \n\t\t")
-		lock.Lock()
-		defer lock.Unlock()
-		for _, v := range requested {
-			builder.WriteString(v.String())
-			builder.WriteString("\n\t\t")
+	case res := <-ch:
+		if res.failed != nil {
+			panic(res.failed)
 		}
-		panic(fmt.Sprintf("Creation failed within the time limit : %d\n\tLast %s\n\tLast %s\n\tlongest time to %s\n\t%s", timeout, lastRequested, lastCreated, longest, builder.String()))
+		return res.injector
+	case <-timer.C:
+		panic(tracer.error(fmt.Errorf("creation failed within the time limit : %s : %w", timeout, context.DeadlineExceeded)))
 	}
 }
 
 // NewImplements returns new empty Implements
 func NewImplements() *Implements {
-	ret := Implements{make(map[string]AbstractModule), nil}
+	ret := Implements{make(map[string]AbstractModule), nil, make(map[string]string), nil, make(map[string][]string), ""}
 	return &ret
 }
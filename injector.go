@@ -1,7 +1,9 @@
 package di
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"path/filepath"
 	"reflect"
 	"runtime"
@@ -57,6 +59,16 @@ type TraceInfo struct {
 	IsSingleton      bool
 	IsBinded         bool
 	IsEager          bool
+
+	// Worker is id of worker which creates eager singletons in parallel.
+	// it is 0 if the instance is not requested by a worker
+	Worker int
+
+	// Module is name of module which configured the binding
+	Module string
+
+	// Source is file:line of code which configured the binding
+	Source string
 }
 
 func (r *TraceInfo) String() string {
@@ -85,16 +97,55 @@ type Injector interface {
 	GetProperty(propName string) string
 	SetProperty(propName string, value string)
 
+	// SetProperties sets all properties at once
+	SetProperties(props map[string]string)
+
+	// WatchProperty registers watcher which is called when the property is changed.
+	// propName can be a pattern like db.* and it returns function to unregister the watcher.
+	// SetProperty returns after watchers of the change are called, but if a watcher sets properties,
+	// the changes are notified after the current watcher returns.
+	// a property having placeholder is changed when the referred property is changed
+	WatchProperty(propName string, watcher PropertyWatcher) func()
+
+	// Properties returns snapshot of properties sorted by name
+	Properties() []Property
+
 	GetInstancesOf(ptrToType interface{}) []interface{}
 	InjectMembers(ptrToStruct interface{})
 	InjectAndCall(function interface{}) interface{}
 	InjectValue(ptrToInterface interface{})
+
+	// InstalledModules returns modules installed to the injector in installation order
+	InstalledModules() []ModuleInfo
+
+	// EagerSingletons returns types of eager singletons in creation order
+	EagerSingletons() []reflect.Type
+
+	// GetInstanceCtx returns instance like GetInstance, but creation is aborted if ctx is done
+	// and it returns error instead of panic
+	GetInstanceCtx(ctx context.Context, ptrToType interface{}) (interface{}, error)
+
+	// Stats returns counters of bindings sorted by type name
+	Stats() []BindingStats
 }
 
 type injectorImpl struct {
 	binder        *Binder
-	props         map[string]string
+	props         *propertyStore
 	traceCallback TraceCallback
+	eagerOrder    []reflect.Type
+
+	// structPlans and callPlans are cached injection plans
+	structPlans sync.Map
+	callPlans   sync.Map
+
+	// singletonWaitTimeout limits waiting for a singleton created by another goroutine
+	singletonWaitTimeout time.Duration
+
+	// logger logs lifecycle of the injector. it can be nil
+	logger *slog.Logger
+
+	stats *injectorStats
 }
 
 type injectorContext struct {
@@ -105,11 +156,29 @@ type injectorContext struct {
 	refererStack  []reflect.Type
 	traceCallback TraceCallback
 	lock          sync.Mutex
+
+	// worker is id of worker creating eager singletons in parallel
+	worker int
+
+	// ctx is context of GetInstanceCtx or NewInjectorContext. it can be nil
+	ctx context.Context
+}
+
+func newInjectorContext(injector *injectorImpl) *injectorContext {
+	return &injectorContext{injector, make(map[reflect.Type]bool), nil, nil, injector.traceCallback, sync.Mutex{}, 0, nil}
 }
 
 func (r *injectorImpl) GetInstance(ptrToType interface{}) interface{} {
 	//fmt.Println("impl getIns")
-	context := injectorContext{r, make(map[reflect.Type]bool), nil, nil, r.traceCallback, sync.Mutex{}}
+	if r.traceCallback == nil {
+		if p := r.binder.providers[reflect.TypeOf(ptrToType)]; p != nil {
+			if ins, ok := p.readyInstance(); ok {
+				return ins
+			}
+		}
+	}
+
+	context := newInjectorContext(r)
 	return context.GetInstance(ptrToType)
 }
 
@@ -120,33 +189,61 @@ func (r *injectorImpl) GetInstancesOf(ptrToType interface{}) []interface{} {
 
 func (r *injectorImpl) getInstanceByType(t reflect.Type) interface{} {
 	//fmt.Println("impl getIns")
-	context := injectorContext{r, make(map[reflect.Type]bool), nil, nil, r.traceCallback, sync.Mutex{}}
+	context := newInjectorContext(r)
 	return context.getInstanceByType(t)
 }
 
 func (r *injectorImpl) InjectMembers(ptrToStruct interface{}) {
 	//fmt.Println("impl getIns")
-	context := injectorContext{r, make(map[reflect.Type]bool), nil, nil, r.traceCallback, sync.Mutex{}}
+	context := newInjectorContext(r)
 	context.InjectMembers(ptrToStruct)
 }
 
 func (r *injectorImpl) InjectAndCall(function interface{}) interface{} {
 	//fmt.Println("impl getIns")
-	context := injectorContext{r, make(map[reflect.Type]bool), nil, nil, r.traceCallback, sync.Mutex{}}
+	context := newInjectorContext(r)
 	return context.InjectAndCall(function)
 }
 
 func (r *injectorImpl) InjectValue(ptrToInterface interface{}) {
-	context := injectorContext{r, make(map[reflect.Type]bool), nil, nil, r.traceCallback, sync.Mutex{}}
+	context := newInjectorContext(r)
 	context.InjectValue(ptrToInterface)
 }
 
+func (r *injectorImpl) InstalledModules() []ModuleInfo {
+	return r.binder.modules.infos()
+}
+
+func (r *injectorImpl) EagerSingletons() []reflect.Type {
+	return slices.Clone(r.eagerOrder)
+}
+
 func (r *injectorImpl) GetProperty(propName string) string {
-	return r.props[propName]
+	return r.props.get(propName)
 }
 
 func (r *injectorImpl) SetProperty(propName string, value string) {
-	r.props[propName] = value
+	r.props.setAll(map[string]string{propName: value})
+}
+
+func (r *injectorImpl) SetProperties(props map[string]string) {
+	r.props.setAll(props)
+}
+
+func (r *injectorImpl) WatchProperty(propName string, watcher PropertyWatcher) func() {
+	return r.props.watch(propName, watcher)
+}
+
+func (r *injectorImpl) Properties() []Property {
+	return r.props.snapshot()
+}
+
+func (r *injectorContext) InstalledModules() []ModuleInfo {
+	return r.injector.InstalledModules()
+}
+
+func (r *injectorContext) EagerSingletons() []reflect.Type {
+	return r.injector.EagerSingletons()
 }
 
 func (r *injectorContext) GetProperty(propName string) string {
@@ -157,7 +254,24 @@ func (r *injectorContext) SetProperty(propName string, value string) {
 	r.injector.SetProperty(propName, value)
 }
 
+func (r *injectorContext) SetProperties(props map[string]string) {
+	r.injector.SetProperties(props)
+}
+
+func (r *injectorContext) WatchProperty(propName string, watcher PropertyWatcher) func() {
+	return r.injector.WatchProperty(propName, watcher)
+}
+
+func (r *injectorContext) Properties() []Property {
+	return r.injector.Properties()
+}
+
 func (r *injectorContext) createJitBinding(binder *Binder, bindType reflect.Type, actualType reflect.Type) *Binding {
+	var counters *bindingCounters
+	if r.injector.stats != nil {
+		counters = r.injector.stats.jitCounters(bindType)
+	}
+
 	return &Binding{
 		binder: binder,
 		tpe:    bindType,
@@ -173,6 +287,7 @@ func (r *injectorContext) createJitBinding(binder *Binder, bindType reflect.Type
 		isEager:       false,
 		isFallback:    false,
 		isDecoratorOf: false,
+		counters:      counters,
 	}
 }
 
@@ -204,6 +319,7 @@ func (r *injectorContext) createInstance(t reflect.Type, p *Binding) interface{}
 	var referer reflect.Type
 
 	r.paninOnLoop(t)
+	r.panicOnAbort(t)
 
 	r.withLock(func() {
 		if len(r.stack) > 0 {
@@ -227,15 +343,38 @@ func (r *injectorContext) createInstance(t reflect.Type, p *Binding) interface{}
 	if r.traceCallback != nil {
 		r.traceCallback(&TraceInfo{
 			TraceType:     InstanceWillBeCreated,
+			Worker:        r.worker,
+			IsSingleton:   p.isSingleton,
+			IsEager:       p.isEager,
+			Module:        p.module,
+			Source:        p.source,
 			RequestedType: t,
 			Referer:       referer,
 		})
 	}
-	ret := p.provider(r)
+	completed := false
+	if p.counters != nil {
+		defer func() {
+			if !completed {
+				p.counters.failures.Add(1)
+			}
+		}()
+	}
+
+	ret := p.provide(r)
 	after := time.Now()
+	completed = true
+	if p.counters != nil {
+		p.counters.created(after.Sub(before))
+	}
 	if r.traceCallback != nil {
 		r.traceCallback(&TraceInfo{
 			TraceType:     InstanceCreated,
+			Worker:        r.worker,
+			IsSingleton:   p.isSingleton,
+			IsEager:       p.isEager,
+			Module:        p.module,
+			Source:        p.source,
 			RequestedType: t,
 			Referer:       referer,
 			IsCreatedNow:  true,
@@ -286,6 +425,16 @@ func (r *injectorContext) getInstanceByBinding(p *Binding) interface{} {
 		return nil
 	}
 
+	if r.traceCallback == nil {
+		if ins, ok := p.readyInstance(); ok {
+			return ins
+		}
+	}
+
+	if p.counters != nil {
+		p.counters.requests.Add(1)
+	}
+
 	var referer reflect.Type
 
 	r.withLock(func() {
@@ -306,11 +455,14 @@ func (r *injectorContext) getInstanceByBinding(p *Binding) interface{} {
 
 		r.traceCallback(&TraceInfo{
 			TraceType:     InstanceRequest,
+			Worker:        r.worker,
 			RequestedType: p.tpe,
 			Referer:       referer,
 			IsBinded:      true,
 			IsSingleton:   p.isSingleton,
 			IsEager:       p.isEager,
+			Module:        p.module,
+			Source:        p.source,
 		})
 
 	}
@@ -318,23 +470,34 @@ func (r *injectorContext) getInstanceByBinding(p *Binding) interface{} {
 	ret := func() interface{} {
 		if p != nil {
 			if p.isSingleton {
-				created := false
 				r.paninOnLoop(p.tpe)
-				p.singletonOnce.Do(func() {
-					if p.provider != nil {
-						ins := r.createInstance(p.tpe, p)
-						if ins != nil {
-							ins = r.wrapInterceptor(p.tpe, ins)
-						}
+				if p.refreshPatterns != nil {
+					r.lockFresh(p)
+					defer p.refreshLock.RUnlock()
+				}
 
-						p.instance = ins
-						created = true
-					}
+				created := false
+				r.buildSingleton(p, func() {
+					defer p.resetOnFailure()
+					p.singletonOnce.Do(func() {
+						if p.provider != nil {
+							p.builtGen.Store(p.refreshGen.Load())
+							ins := r.createInstance(p.tpe, p)
+							if ins != nil {
+								ins = r.wrapInterceptor(p.tpe, ins)
+							}
+
+							p.instance = ins
+							created = true
+							p.created.Store(true)
+						}
+					})
 				})
 				if created && p.instance != nil {
 					r.callDecorators(p.tpe)
 				}
 				ret := p.instance
+				p.setReady(ret)
 				return ret
 			}
 			ret := r.createInstance(p.tpe, p)
@@ -350,11 +513,14 @@ func (r *injectorContext) getInstanceByBinding(p *Binding) interface{} {
 
 		r.traceCallback(&TraceInfo{
 			TraceType:        InstanceReturned,
+			Worker:           r.worker,
 			RequestedType:    p.tpe,
 			Referer:          referer,
 			IsBinded:         true,
 			IsSingleton:      p.isSingleton,
 			IsEager:          p.isEager,
+			Module:           p.module,
+			Source:           p.source,
 			ReturnedInstance: ret,
 		})
 	}
@@ -366,6 +532,9 @@ func (r *injectorContext) getInstanceByType(t reflect.Type) interface{} {
 	p := r.getBinding(t)
 
 	if p == nil {
+		if r.traceCallback == nil {
+			return nil
+		}
 
 		var referer reflect.Type
 		if len(r.refererStack) > 0 {
@@ -374,6 +543,7 @@ func (r *injectorContext) getInstanceByType(t reflect.Type) interface{} {
 
 		r.traceCallback(&TraceInfo{
 			TraceType:     InstanceRequest,
+			Worker:        r.worker,
 			RequestedType: t,
 			Referer:       referer,
 			IsBinded:      false,
@@ -383,6 +553,7 @@ func (r *injectorContext) getInstanceByType(t reflect.Type) interface{} {
 
 		r.traceCallback(&TraceInfo{
 			TraceType:        InstanceReturned,
+			Worker:           r.worker,
 			RequestedType:    t,
 			Referer:          referer,
 			IsBinded:         false,
@@ -416,6 +587,11 @@ func (r *injectorContext) GetInstancesOf(ptrToType interface{}) []interface{} {
 type injectTag struct {
 	inject  bool
 	nilable bool
+
+	// prop is name of property to inject
+	prop         string
+	defaultValue string
+	hasDefault   bool
 }
 
 func contains(s []string, e string) bool {
@@ -431,17 +607,28 @@ func hasInjectTag(tag reflect.StructTag) injectTag {
 	value, ok := tag.Lookup("di")
 	if ok {
 		if value == "inject" {
-			return injectTag{true, false}
+			return injectTag{inject: true, nilable: false}
 		}
 		sp := strings.Split(value, ",")
-		if contains(sp, "inject") {
-			if contains(sp, "nilable") {
-				return injectTag{true, true}
+		ret := injectTag{inject: false, nilable: true}
+		for i, v := range sp {
+			if strings.HasPrefix(v, "prop=") {
+				ret.prop = strings.TrimPrefix(v, "prop=")
+			} else if strings.HasPrefix(v, "default=") {
+				// default value may have comma, so it should be the last
+				ret.defaultValue = strings.TrimPrefix(strings.Join(sp[i:], ","), "default=")
+				ret.hasDefault = true
+				sp = sp[:i]
+				break
 			}
-			return injectTag{true, false}
 		}
+		if contains(sp, "inject") {
+			ret.inject = true
+			ret.nilable = contains(sp, "nilable")
+		}
+		return ret
 	}
-	return injectTag{false, true}
+	return injectTag{inject: false, nilable: true}
 }
 
 func isNil(v reflect.Value) bool {
@@ -462,7 +649,7 @@ func (r *injectorContext) clone() *injectorContext {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
-	ret := injectorContext{r.injector, maps.Clone(r.loopCheck), slices.Clone(r.stack), slices.Clone(r.refererStack), r.traceCallback, sync.Mutex{}}
+	ret := injectorContext{r.injector, maps.Clone(r.loopCheck), slices.Clone(r.stack), slices.Clone(r.refererStack), r.traceCallback, sync.Mutex{}, r.worker, r.ctx}
 	return &ret
 }
 func (r *injectorContext) InjectAndCall(function interface{}) interface{} {
@@ -478,59 +665,58 @@ func (r *injectorContext) InjectAndCall(function interface{}) interface{} {
 		panic(fmt.Sprintf("can't inject variadic function %v (type %v)", function, ftype))
 	}
 
-	args := []reflect.Value{}
-	for i := 0; i < ftype.NumIn(); i++ {
-		argtype := ftype.In(i)
+	args := make([]reflect.Value, ftype.NumIn())
+	for i, a := range r.injector.callPlan(ftype) {
+		argtype := a.tpe
 
-		lazyType := reflectfp.MatchLazyEval(argtype)
+		switch a.kind {
+		case argProperty:
+			pv := reflect.New(argtype).Interface().(propertyParam)
+			pv.setValue(r.propertyValue(a.tag, a.valueType, func() string {
+				fname := filepath.Base(runtime.FuncForPC(reflect.ValueOf(function).Pointer()).Name())
+				return fmt.Sprintf("argument of function %s at index %d", fname, i)
+			}))
+			args[i] = reflect.ValueOf(pv).Elem()
 
-		optType := reflectfp.MatchOption(argtype)
+		case argProvider:
+			args[i], _ = r.providerValue(argtype)
 
-		bindtype := argtype
-		if optType.IsDefined() {
-			bindtype = reflect.PtrTo(optType.Get())
-		} else if lazyType.IsDefined() {
-			bindtype = reflect.PtrTo(lazyType.Get())
-		}
-
-		if bindtype.Kind() != reflect.Ptr {
-			bindtype = reflect.PtrTo(argtype)
-		}
+		case argIn:
+			nv := reflect.New(argtype)
+			r.injectMembers(nv.Interface(), true)
+			args[i] = nv.Elem()
 
-		binding := r.getBinding(bindtype)
-		if binding != nil && optType.IsEmpty() && lazyType.IsDefined() {
-			lazyv := reflectfp.LazyCall(argtype, func() reflect.Value {
+		case argLazy:
+			binding := a.binding
+			args[i] = reflectfp.LazyCall(argtype, func() reflect.Value {
 
 				lazyCtx := r.clone()
 
 				instance := lazyCtx.getInstanceByBinding(binding)
 				return reflect.ValueOf(instance)
-			})
-			args = append(args, lazyv.Get())
-		} else {
-			if optType.IsEmpty() && binding == nil && argtype.Kind() == reflect.Ptr && bindtype.Elem().Kind() == reflect.Struct && r.injector.binder.providers[bindtype] == nil {
-				binding = r.createJitBinding(r.injector.binder, argtype, bindtype)
+			}).Get()
+
+		default:
+			binding := a.binding
+			if a.kind == argJit {
+				binding = r.createJitBinding(r.injector.binder, argtype, a.key)
 			}
 			instance := r.getInstanceByBinding(binding)
 
-			if optType.IsDefined() {
+			if a.kind == argOption {
 				if instance == nil {
-					args = append(args, reflectfp.None(argtype).Get())
+					args[i] = reflectfp.None(argtype).Get()
 				} else {
-					somev := reflectfp.Some(argtype, reflect.ValueOf(instance))
-					args = append(args, somev.Get())
+					args[i] = reflectfp.Some(argtype, reflect.ValueOf(instance)).Get()
 				}
-
 			} else {
 				if instance == nil {
 					fname := filepath.Base(runtime.FuncForPC(reflect.ValueOf(function).Pointer()).Name())
 					panic(fmt.Sprintf("%s is Not Binded. So Can't Inject argument of function %s at index %d", argtype.String(), fname, i))
-				} else {
-					args = append(args, reflect.ValueOf(instance).Convert(argtype))
 				}
+				args[i] = reflect.ValueOf(instance).Convert(argtype)
 			}
 		}
-
 	}
 
 	resultValue := reflect.ValueOf(function).Call(args)
@@ -558,6 +744,12 @@ func (r *injectorContext) InjectAndCall(function interface{}) interface{} {
 }
 
 func (r *injectorContext) InjectMembers(ptrToStruct interface{}) {
+	r.injectMembers(ptrToStruct, false)
+}
+
+// injectMembers injects fields of struct.
+// if requireAll is true, every exported field is injected as if it has inject tag
+func (r *injectorContext) injectMembers(ptrToStruct interface{}, requireAll bool) {
 	//fmt.Println("context getIns")
 
 	ptrvalue := reflect.ValueOf(ptrToStruct)
@@ -574,96 +766,63 @@ func (r *injectorContext) InjectMembers(ptrToStruct interface{}) {
 
 	rv := ptrvalue.Elem()
 
-	explicitInject := false
-	for i := 0; i < rv.NumField(); i++ {
-		fieldType := t.Field(i)
-		if hasInjectTag(fieldType.Tag).inject {
-			explicitInject = true
-			break
-		}
-	}
+	for _, m := range r.injector.structPlan(t, requireAll) {
+		field := rv.Field(m.index)
 
-	for i := 0; i < rv.NumField(); i++ {
-		field := rv.Field(i)
-		fieldType := t.Field(i)
-
-		switch field.Kind() {
-		case reflect.Func:
-			if field.IsNil() && field.CanSet() {
-				if tag := hasInjectTag(fieldType.Tag); explicitInject == false || tag.inject {
-					res := r.getInstanceByType(reflect.PtrTo(fieldType.Type))
-					if res != nil {
-						//field.Elem().Set(reflect.ValueOf(res))
-						field.Set(reflect.ValueOf(res))
-					} else if explicitInject && tag.nilable == false {
-						panic(fmt.Sprintf("%s is Not Binded. So Can't Inject to %s.%s", fieldType.Type.String(), t.String(), fieldType.Name))
-					}
-				}
+		switch m.kind {
+		case memberProperty:
+			field.Set(r.propertyValue(m.tag, m.field.Type, m.target))
 
+		case memberInstance:
+			if m.nilOnly && !field.IsNil() {
+				continue
 			}
-
-		case reflect.Struct:
-			if field.CanSet() {
-				if explicitInject == false || hasInjectTag(fieldType.Tag).inject {
-					if valType, ok := reflectfp.MatchOption(fieldType.Type).Unapply(); ok {
-						res := r.getInstanceByType(reflect.PtrTo(valType))
-						if res != nil {
-							field.Set(reflectfp.Some(fieldType.Type, reflect.ValueOf(res)).Get())
-						} else {
-							field.Set(reflectfp.None(fieldType.Type).Get())
-						}
-
-					} else if valType, ok := reflectfp.MatchLazyEval(fieldType.Type).Unapply(); ok {
-						res := reflectfp.LazyCall(fieldType.Type, func() reflect.Value {
-							lazyCtx := r.clone()
-							return reflect.ValueOf(lazyCtx.getInstanceByType(reflect.PtrTo(valType)))
-						})
-						field.Set(res.Get())
-					} else {
-						r.InjectMembers(field.Addr().Interface())
-					}
+			res := r.instanceOf(m.key, m.binding)
+			if res != nil {
+				if m.convert {
+					field.Set(reflect.ValueOf(res).Convert(m.field.Type))
+				} else {
+					field.Set(reflect.ValueOf(res))
 				}
+			} else if m.required {
+				panic(fmt.Sprintf("%s is Not Binded. So Can't Inject to %s.%s", m.field.Type.String(), t.String(), m.field.Name))
 			}
-		case reflect.Ptr:
-			if field.IsNil() && field.CanSet() {
-				if tag := hasInjectTag(fieldType.Tag); explicitInject == false || tag.inject {
-					res := r.getInstanceByType(fieldType.Type)
-					if res != nil {
-						//field.Elem().Set(reflect.ValueOf(res))
-						field.Set(reflect.ValueOf(res))
-					} else if explicitInject && tag.nilable == false {
-						panic(fmt.Sprintf("%s is Not Binded. So Can't Inject to %s.%s", fieldType.Type.String(), t.String(), fieldType.Name))
-					}
 
-				}
-			}
-		case reflect.Interface:
-			if field.IsNil() && field.CanSet() {
-				if tag := hasInjectTag(fieldType.Tag); explicitInject == false || tag.inject {
-					res := r.getInstanceByType(reflect.PtrTo(fieldType.Type))
-					if res != nil {
-						//field.Elem().Set(reflect.ValueOf(res))
-						field.Set(reflect.ValueOf(res))
-					} else if explicitInject && tag.nilable == false {
-						panic(fmt.Sprintf("%s is Not Binded. So Can't Inject to %s.%s", fieldType.Type.String(), t.String(), fieldType.Name))
-					}
-				}
-			}
-		default:
-			if field.CanSet() {
-				if tag := hasInjectTag(fieldType.Tag); tag.inject {
-					res := r.getInstanceByType(reflect.PtrTo(fieldType.Type))
-					if res != nil {
-						field.Set(reflect.ValueOf(res).Convert(fieldType.Type))
-					} else if explicitInject && tag.nilable == false {
-						panic(fmt.Sprintf("%s is Not Binded. So Can't Inject to %s.%s", fieldType.Type.String(), t.String(), fieldType.Name))
-					}
-				}
+		case memberProvider:
+			pv, _ := r.providerValue(m.field.Type)
+			field.Set(pv)
+
+		case memberOption:
+			res := r.instanceOf(m.key, m.binding)
+			if res != nil {
+				field.Set(reflectfp.Some(m.field.Type, reflect.ValueOf(res)).Get())
+			} else {
+				field.Set(reflectfp.None(m.field.Type).Get())
 			}
+
+		case memberLazy:
+			key := m.key
+			res := reflectfp.LazyCall(m.field.Type, func() reflect.Value {
+				lazyCtx := r.clone()
+				return reflect.ValueOf(lazyCtx.getInstanceByType(key))
+			})
+			field.Set(res.Get())
+
+		case memberNested:
+			r.InjectMembers(field.Addr().Interface())
 		}
 	}
 }
 
+// instanceOf returns instance of the binding resolved by injection plan.
+// if key is not binded, it is requested by type to trace it
+func (r *injectorContext) instanceOf(key reflect.Type, binding *Binding) interface{} {
+	if binding != nil {
+		return r.getInstanceByBinding(binding)
+	}
+	return r.getInstanceByType(key)
+}
+
 func (r *injectorContext) InjectValue(ptrToInterface interface{}) {
 	// defer func() {
 	// 	if r := recover(); r != nil {
@@ -716,8 +875,6 @@ func NewInjectorWithTimeout(implements *Implements, moduleNames []string, timeou
 // CreateInjector creates new Injector with provided modules
 func CreateInjector(modules ...AbstractModule) Injector {
 	impls := NewImplements()
-	for _, m := range modules {
-		impls.AddBind(m.Configure)
-	}
+	impls.anonymousModule = append(impls.anonymousModule, modules...)
 	return impls.NewInjector(nil)
 }
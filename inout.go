@@ -0,0 +1,214 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// In is embedded to a struct to make it a parameter struct of constructor.
+// every exported field of the parameter struct is injected and it panics if the field type is not binded.
+// fields tagged with `di:"nilable"` can be nil and fp.Option, lazy.Eval, Provider and property fields are also supported
+//
+//	type RepositoryParams struct {
+//		di.In
+//		DB    *sql.DB
+//		Cache Cache `di:"nilable"`
+//	}
+//
+//	func NewRepository(params RepositoryParams) *Repository
+type In struct{}
+
+var inType = reflect.TypeOf(In{})
+
+// isInStruct returns whether t is a struct embedding In
+func isInStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == inType {
+			return true
+		}
+	}
+	return false
+}
+
+// memberTag returns inject tag of the field.
+// if requireAll is true, the field is injected even if it has no inject tag
+func memberTag(field reflect.StructField, requireAll bool) injectTag {
+	ret := hasInjectTag(field.Tag)
+	if requireAll && !ret.inject {
+		value, ok := field.Tag.Lookup("di")
+		ret.inject = value != "-"
+		ret.nilable = ok && contains(strings.Split(value, ","), "nilable")
+	}
+	return ret
+}
+
+// Out is embedded to a struct returned by constructor to provide each exported field as its own binding.
+// the constructor is called only once and every field binding is singleton
+//
+//	type RedisBundle struct {
+//		di.Out
+//		Client  *redis.Client
+//		Checker HealthChecker
+//	}
+//
+//	binder.Provide(NewRedisBundle)
+type Out struct{}
+
+var outType = reflect.TypeOf(Out{})
+
+// isOutStruct returns whether t is a struct embedding Out
+func isOutStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == outType {
+			return true
+		}
+	}
+	return false
+}
+
+// bindKeyOf returns binding key type of instance type.
+// pointer type is key of itself and other types are keyed by pointer to them
+func bindKeyOf(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Ptr {
+		return t
+	}
+	return reflect.PtrTo(t)
+}
+
+// ProvideOption changes bindings of the constructor preceding it in Provide arguments
+type ProvideOption interface {
+	applyProvide(b *Binder, binding *Binding)
+}
+
+type asOption struct {
+	key reflect.Type
+}
+
+func (r asOption) applyProvide(b *Binder, binding *Binding) {
+	intf := r.key.Elem()
+	if intf.Kind() != reflect.Interface || !(binding.tpe.Implements(intf) || binding.tpe.Elem().Implements(intf)) {
+		panic(fmt.Sprintf("Provide : %s can't be binded as %s", binding.tpe, r.key))
+	}
+
+	b.bind(&Binding{
+		binder:       b,
+		tpe:          r.key,
+		isSingleton:  true,
+		dependencies: []reflect.Type{binding.tpe},
+		provider: func(injector Injector) interface{} {
+			return injector.GetInstance(reflect.Zero(binding.tpe).Interface())
+		},
+	})
+}
+
+// Provide binds constructors to types of their first result.
+// pointer result is binded to its type and interface result I is binded to *I.
+// if the result is a struct embedding Out, each exported field of the struct is binded to its type.
+// ProvideOption like As can follow a constructor to bind it to other types.
+//
+//	binder.Provide(NewA, NewB, di.As[Reader](), NewC)
+func (b *Binder) Provide(constructorsAndOptions ...interface{}) {
+	var last *Binding
+	for _, arg := range constructorsAndOptions {
+		if opt, ok := arg.(ProvideOption); ok {
+			if last == nil {
+				panic("Provide : option should follow a constructor")
+			}
+			opt.applyProvide(b, last)
+			continue
+		}
+
+		constructor := arg
+		ftype := reflect.TypeOf(constructor)
+		if ftype == nil || ftype.Kind() != reflect.Func || ftype.NumOut() == 0 {
+			panic(fmt.Sprintf("Provide : %v is not constructor", ftype))
+		}
+
+		resultType := ftype.Out(0)
+		if isOutStruct(resultType) {
+			last = b.provideOut(constructor, resultType)
+			continue
+		}
+
+		if resultType.Kind() != reflect.Ptr && resultType.Kind() != reflect.Interface {
+			panic(fmt.Sprintf("Provide : result type %s of constructor should be pointer or interface", resultType))
+		}
+
+		last = &Binding{
+			binder:       b,
+			tpe:          bindKeyOf(resultType),
+			isSingleton:  true,
+			dependencies: constructorDependencies(constructor),
+			provider: func(injector Injector) interface{} {
+				return firstResult(injector.InjectAndCall(constructor))
+			},
+		}
+		b.bind(last)
+	}
+}
+
+// provideOut binds pointer to the result struct and its fields.
+// field bindings get the result struct from the injector, so the constructor is called once
+func (b *Binder) provideOut(constructor interface{}, resultType reflect.Type) *Binding {
+	resultKey := reflect.PtrTo(resultType)
+	ret := &Binding{
+		binder:       b,
+		tpe:          resultKey,
+		isSingleton:  true,
+		dependencies: constructorDependencies(constructor),
+		provider: func(injector Injector) interface{} {
+			result := reflect.New(resultType)
+			result.Elem().Set(reflect.ValueOf(firstResult(injector.InjectAndCall(constructor))))
+			return result.Interface()
+		},
+	}
+	b.bind(ret)
+
+	for i := 0; i < resultType.NumField(); i++ {
+		field := resultType.Field(i)
+		if field.Anonymous && field.Type == outType || !field.IsExported() || field.Tag.Get("di") == "-" {
+			continue
+		}
+
+		index := i
+		b.bind(&Binding{
+			binder:       b,
+			tpe:          bindKeyOf(field.Type),
+			isSingleton:  true,
+			dependencies: []reflect.Type{resultKey},
+			provider: func(injector Injector) interface{} {
+				result := injector.GetInstance(reflect.Zero(resultKey).Interface())
+				if result == nil {
+					return nil
+				}
+				fv := reflect.ValueOf(result).Elem().Field(index)
+				if isNil(fv) {
+					return nil
+				}
+				return fv.Interface()
+			},
+		})
+	}
+	return ret
+}
+
+// firstResult returns first result of InjectAndCall.
+// if the function returns error as the last result, it panics with the error
+func firstResult(ret interface{}) interface{} {
+	list, ok := ret.([]interface{})
+	if !ok {
+		return ret
+	}
+
+	if err, ok := list[len(list)-1].(error); ok && err != nil {
+		panic(err)
+	}
+	return list[0]
+}
@@ -0,0 +1,207 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/csgura/di"
+	"github.com/csgura/fp"
+	"github.com/csgura/fp/lazy"
+)
+
+type serviceParams struct {
+	di.In
+
+	Value    Value1
+	Optional fp.Option[Value2]
+	Lazy     lazy.Eval[Value1]
+	Nilable  Value3 `di:"nilable"`
+	Ignored  Value3 `di:"-"`
+	Port     PrometheusPort
+	Timeout  int `di:"prop=service.timeout,default=10"`
+}
+
+type service struct {
+	params serviceParams
+}
+
+func TestInStruct(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddBind(func(binder *di.Binder) {
+		di.Bind[Value1](binder).ToInstance(&ValueImpl{"Value1"})
+		di.Bind[Value3](binder).ToInstance(&ValueImpl{"Value3"})
+		di.Bind[PrometheusPort](binder).ToInstance(8080)
+		di.Bind[*service](binder).ToConstructor(func(params serviceParams) *service {
+			return &service{params}
+		})
+	})
+
+	injector := impls.NewInjector(nil)
+	params := di.GetInstance[*service](injector).params
+
+	if params.Value.Value() != "Value1" || params.Lazy.Get().Value() != "Value1" {
+		t.Errorf("Value1 not injected")
+	}
+
+	if params.Optional.IsDefined() {
+		t.Errorf("Value2 is not binded")
+	}
+
+	if params.Nilable == nil || params.Ignored != nil {
+		t.Errorf("unexpected Value3 : %v, %v", params.Nilable, params.Ignored)
+	}
+
+	if params.Port != 8080 || params.Timeout != 10 {
+		t.Errorf("unexpected params : %v, %v", params.Port, params.Timeout)
+	}
+}
+
+func TestInStructNotBinded(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+
+	injector := di.CreateInjector(di.BindFunc(func(binder *di.Binder) {
+		di.Bind[Value3](binder).ToInstance(&ValueImpl{"Value3"})
+		di.Bind[PrometheusPort](binder).ToInstance(8080)
+	}))
+
+	injector.InjectAndCall(func(params serviceParams) *service {
+		return &service{params}
+	})
+}
+
+type healthChecker interface {
+	Check() bool
+}
+
+type redisClient struct {
+	url string
+}
+
+func (r *redisClient) Check() bool {
+	return r.url != ""
+}
+
+type redisBundle struct {
+	di.Out
+
+	Client  *redisClient
+	Checker healthChecker
+	Value   Value1
+}
+
+func TestOutStruct(t *testing.T) {
+	called := 0
+
+	injector := di.CreateInjector(di.BindFunc(func(binder *di.Binder) {
+		di.Bind[PrometheusAddress](binder).ToInstance("localhost")
+		binder.Provide(func(addr PrometheusAddress) redisBundle {
+			called++
+			client := &redisClient{string(addr)}
+			return redisBundle{Client: client, Checker: client}
+		})
+	}))
+
+	client := di.GetInstance[*redisClient](injector)
+	checker := di.GetInstance[healthChecker](injector)
+
+	if client.url != "localhost" || checker != client {
+		t.Errorf("unexpected bundle : %v, %v", client, checker)
+	}
+
+	if di.GetInstanceOpt[Value1](injector).IsDefined() {
+		t.Errorf("nil field is binded")
+	}
+
+	bundle := di.GetInstance[*redisBundle](injector)
+	if bundle.Client != client {
+		t.Errorf("unexpected bundle : %v", bundle)
+	}
+
+	injector.InjectAndCall(func(b *redisBundle) {
+		if b != bundle {
+			t.Errorf("unexpected bundle : %v", b)
+		}
+	})
+
+	if called != 1 {
+		t.Errorf("constructor called %d times", called)
+	}
+}
+
+func TestOutStructDuplicated(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+
+	impls := di.NewImplements()
+	impls.AddImplement("Client", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*redisClient](binder).ToInstance(&redisClient{})
+	}))
+	impls.AddImplement("Bundle", di.BindFunc(func(binder *di.Binder) {
+		binder.Provide(func() redisBundle {
+			return redisBundle{}
+		})
+	}))
+	impls.NewInjector([]string{"Client", "Bundle"})
+}
+
+type provideA struct{}
+
+type provideB struct {
+	a *provideA
+}
+
+func (r *provideB) Value() string {
+	return "B"
+}
+
+func newProvideA() *provideA {
+	return &provideA{}
+}
+
+func newProvideB(a *provideA) (*provideB, error) {
+	return &provideB{a}, nil
+}
+
+func newProvideClient(b *provideB) client {
+	return &clientImpl{}
+}
+
+func TestProvide(t *testing.T) {
+	injector := di.CreateInjector(di.BindFunc(func(binder *di.Binder) {
+		binder.Provide(newProvideA, newProvideB, di.As[Value1](), newProvideClient)
+	}))
+
+	b := di.GetInstance[*provideB](injector)
+	if b.a != di.GetInstance[*provideA](injector) {
+		t.Errorf("*provideA not injected")
+	}
+
+	if v := di.GetInstance[Value1](injector); v != b {
+		t.Errorf("*provideB is not binded as Value1 : %v", v)
+	}
+
+	if di.GetInstance[client](injector).Do("B") != "hello B" {
+		t.Errorf("client not binded")
+	}
+}
+
+func TestProvideAsNotImplemented(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+
+	di.CreateInjector(di.BindFunc(func(binder *di.Binder) {
+		binder.Provide(newProvideA, di.As[Value1]())
+	}))
+}
@@ -1,12 +1,145 @@
 package di
 
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// KeyedModule is a module that has its own identity.
+// modules having same key are installed only once
+type KeyedModule interface {
+	AbstractModule
+	ModuleKey() string
+}
+
+// ModuleInfo describes a installed module
+type ModuleInfo struct {
+	Name   string
+	Module AbstractModule
+
+	// Path is names of modules from the top level module to this module
+	// which installed this module first
+	Path []string
+}
+
+type moduleKey string
+
+type moduleRegistry struct {
+	installed map[interface{}]*ModuleInfo
+	list      []*ModuleInfo
+	path      []string
+
+	// keys are identities of modules in path. it is nil for module which has no identity
+	keys []interface{}
+
+	// parent is registry of the binder which installs ConditionalModule.
+	// modules installed to parent are not installed again, but modules installed to this are not visible to parent
+	parent *moduleRegistry
+}
+
+func newModuleRegistry() *moduleRegistry {
+	return &moduleRegistry{installed: make(map[interface{}]*ModuleInfo)}
+}
+
+func (r *moduleRegistry) enter(name string, key interface{}) {
+	r.path = append(r.path, name)
+	r.keys = append(r.keys, key)
+}
+
+func (r *moduleRegistry) leave() {
+	r.path = r.path[0 : len(r.path)-1]
+	r.keys = r.keys[0 : len(r.keys)-1]
+}
+
+// newConditional returns registry for modules installed by ConditionalModule
+func (r *moduleRegistry) newConditional() *moduleRegistry {
+	ret := newModuleRegistry()
+	ret.parent = r
+	ret.path = slices.Clone(r.path)
+	ret.keys = slices.Clone(r.keys)
+	return ret
+}
+
+// isInstalled returns whether the module is installed to this or parent registry
+func (r *moduleRegistry) isInstalled(key interface{}) bool {
+	for reg := r; reg != nil; reg = reg.parent {
+		if _, exists := reg.installed[key]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *moduleRegistry) infos() []ModuleInfo {
+	ret := make([]ModuleInfo, len(r.list))
+	for i, v := range r.list {
+		ret[i] = *v
+		ret[i].Path = slices.Clone(v.Path)
+	}
+	return ret
+}
+
+// moduleIdentity returns key of module. pointer modules are identified by the pointer, not by pointed value.
+// modules which are not comparable like BindFunc have no identity, so they are configured whenever installed
+func moduleIdentity(module AbstractModule) (interface{}, bool) {
+	if km, ok := module.(KeyedModule); ok {
+		return moduleKey(km.ModuleKey()), true
+	}
+	if reflect.ValueOf(module).Comparable() {
+		return module, true
+	}
+	return nil, false
+}
+
+func moduleName(module AbstractModule) string {
+	switch m := module.(type) {
+	case KeyedModule:
+		return m.ModuleKey()
+	case *combineModule:
+		return "CombineModule"
+	case *orverriden:
+		return "OverrideModule"
+	case BindFunc:
+		return "BindFunc"
+	}
+	return fmt.Sprintf("%T", module)
+}
+
+// Install configures the module to the binder.
+// if the module is already installed, it is skipped.
+// modules are identified by ModuleKey or module value, and modules having no identity like BindFunc are never skipped
+func (b *Binder) Install(module AbstractModule) {
+	b.install(moduleName(module), module)
+}
+
+func (b *Binder) install(name string, module AbstractModule) {
+	reg := b.modules
+
+	key, hasKey := moduleIdentity(module)
+	if hasKey && reg.isInstalled(key) {
+		return
+	}
+
+	reg.enter(name, key)
+	defer reg.leave()
+
+	info := &ModuleInfo{Name: name, Module: module, Path: slices.Clone(reg.path)}
+	if hasKey {
+		reg.installed[key] = info
+	}
+	reg.list = append(reg.list, info)
+
+	module.Configure(b)
+}
+
 type combineModule struct {
 	modules []AbstractModule
 }
 
 func (r *combineModule) Configure(binder *Binder) {
 	for _, m := range r.modules {
-		m.Configure(binder)
+		binder.Install(m)
 	}
 }
 
@@ -23,15 +156,15 @@ type orverriden struct {
 
 func (r *orverriden) Configure(binder *Binder) {
 
-	tempBinder := newBinder()
+	tempBinder := binder.newChildBinder()
 	for _, m := range r.overrides {
-		m.Configure(tempBinder)
+		tempBinder.Install(m)
 	}
 
 	tempBinder.ignoreDuplicate = true
 
 	for _, m := range r.modules {
-		m.Configure(tempBinder)
+		tempBinder.Install(m)
 	}
 
 	binder.merge(tempBinder, true)
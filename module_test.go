@@ -1,6 +1,7 @@
 package di_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/csgura/di"
@@ -223,3 +224,135 @@ func TestDupDefaultsPanic(t *testing.T) {
 	}
 
 }
+
+type requiringModule struct {
+	requires []string
+	bind     func(binder *di.Binder)
+}
+
+func (r *requiringModule) Configure(binder *di.Binder) {
+	if r.bind != nil {
+		r.bind(binder)
+	}
+}
+
+func (r *requiringModule) Requires() []string {
+	return r.requires
+}
+
+func TestModuleRequires(t *testing.T) {
+	impls := di.NewImplements()
+
+	impls.AddImplement("V1", &requiringModule{requires: []string{"MemCache"}, bind: func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToConstructor(func(v2 Value2) Value1 {
+			return &ValueImpl{"Value1:" + v2.Value()}
+		})
+	}})
+
+	impls.AddImplement("MemCache", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value2)(nil)).ToInstance(&ValueImpl{"MemCache"})
+	}))
+
+	// MemCache is required twice. it should be configured only once
+	injector := impls.NewInjector([]string{"V1", "MemCache"})
+	if injector.GetInstance((*Value1)(nil)).(Value1).Value() != "Value1:MemCache" {
+		t.Errorf("Value1 not binded")
+	}
+}
+
+func TestModuleRequiresMissing(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		} else if r != "module MemCache is not implemented (required by V1)" {
+			t.Errorf("unexpected panic : %v", r)
+		}
+	}()
+
+	impls := di.NewImplements()
+	impls.AddImplement("V1", &requiringModule{requires: []string{"MemCache"}})
+
+	impls.NewInjector([]string{"V1"})
+}
+
+func TestModuleRequiresCycle(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		} else if r != "module dependency cycle : A -> B -> C -> A" {
+			t.Errorf("unexpected panic : %v", r)
+		}
+	}()
+
+	impls := di.NewImplements()
+	impls.AddImplement("A", &requiringModule{requires: []string{"B"}})
+	impls.AddImplement("B", &requiringModule{requires: []string{"C"}})
+	impls.AddImplement("C", &requiringModule{requires: []string{"A"}})
+
+	impls.NewInjector([]string{"A"})
+}
+
+type countingModule struct {
+	count int
+}
+
+func (r *countingModule) Configure(binder *di.Binder) {
+	r.count++
+	binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"Value1"})
+}
+
+type keyedModule struct {
+	key string
+}
+
+func (r *keyedModule) Configure(binder *di.Binder) {
+	binder.Bind((*Value2)(nil)).ToInstance(&ValueImpl{r.key})
+}
+
+func (r *keyedModule) ModuleKey() string {
+	return r.key
+}
+
+func TestInstallOnce(t *testing.T) {
+	m := &countingModule{}
+
+	impls := di.NewImplements()
+	impls.AddImplement("Combined", di.CombineModule(m, &keyedModule{"Keyed"}))
+	impls.AddImplement("Counting", m)
+	impls.AddImplement("Keyed", di.BindFunc(func(binder *di.Binder) {
+		binder.Install(&keyedModule{"Keyed"})
+	}))
+
+	injector := impls.NewInjector([]string{"Combined", "Counting", "Keyed"})
+	if m.count != 1 {
+		t.Errorf("module configured %d times", m.count)
+	}
+
+	if injector.GetInstance((*Value2)(nil)).(Value2).Value() != "Keyed" {
+		t.Errorf("Value2 not binded")
+	}
+
+	found := false
+	for _, info := range injector.InstalledModules() {
+		if info.Module == m {
+			found = true
+			if strings.Join(info.Path, " > ") != "Combined > *di_test.countingModule" {
+				t.Errorf("unexpected install path : %v", info.Path)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("counting module is not in installed modules")
+	}
+}
+
+func TestCreateInjectorInstallOnce(t *testing.T) {
+	m := &countingModule{}
+
+	di.CreateInjector(m, di.CombineModule(m))
+	if m.count != 1 {
+		t.Errorf("module configured %d times", m.count)
+	}
+}
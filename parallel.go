@@ -0,0 +1,135 @@
+package di
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"sync"
+	"time"
+)
+
+type injectorOptions struct {
+	parallelEager        int
+	singletonWaitTimeout time.Duration
+	logger               *slog.Logger
+	profiles             []activeProfile
+}
+
+// InjectorOption changes how the injector is created
+type InjectorOption func(options *injectorOptions)
+
+// WithParallelEager creates independent eager singletons concurrently with n workers.
+// an eager singleton is created after eager singletons which its constructor depends on.
+// dependencies are known only from signatures of ToConstructor and Provide, so ToProvider bindings
+// getting instances in the provider are scheduled as independent. they still wait for singletons
+// being created by other workers, but the workers may be blocked meanwhile
+func WithParallelEager(n int) InjectorOption {
+	return func(options *injectorOptions) {
+		options.parallelEager = n
+	}
+}
+
+// WithSingletonWaitTimeout limits how long a goroutine waits for a singleton
+// which is being created by another goroutine. zero means no limit
+func WithSingletonWaitTimeout(timeout time.Duration) InjectorOption {
+	return func(options *injectorOptions) {
+		options.singletonWaitTimeout = timeout
+	}
+}
+
+func newInjectorOptions(options []InjectorOption) *injectorOptions {
+	ret := &injectorOptions{}
+	for _, opt := range options {
+		opt(ret)
+	}
+	return ret
+}
+
+// createEagerSingletons creates eager singletons in eagerOrder with workers.
+// if creation of an eager singleton panics, singletons depending on it are not created
+// and the first panic is propagated to the caller
+func (r *injectorImpl) createEagerSingletons(ctx context.Context, workers int) {
+	if workers <= 1 {
+		for _, t := range r.eagerOrder {
+			context := newInjectorContext(r)
+			context.ctx = ctx
+			context.getInstanceByType(t)
+		}
+		return
+	}
+
+	done := make(map[reflect.Type]chan struct{}, len(r.eagerOrder))
+	for _, t := range r.eagerOrder {
+		done[t] = make(chan struct{})
+	}
+
+	workerIDs := make(chan int, workers)
+	for i := 1; i <= workers; i++ {
+		workerIDs <- i
+	}
+
+	position := make(map[reflect.Type]int, len(r.eagerOrder))
+	for i, t := range r.eagerOrder {
+		position[t] = i
+	}
+
+	var failed interface{}
+	lock := sync.Mutex{}
+	isFailed := func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return failed != nil
+	}
+
+	wg := sync.WaitGroup{}
+	for i, t := range r.eagerOrder {
+		t := t
+
+		// waits only for eager singletons before it, so dependency cycle can't block workers
+		var deps []reflect.Type
+		for _, d := range r.binder.eagerDependencies(r.binder.providers[t]) {
+			if position[d.tpe] < i {
+				deps = append(deps, d.tpe)
+			}
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[t])
+
+			for _, d := range deps {
+				<-done[d]
+			}
+
+			worker := <-workerIDs
+			defer func() {
+				workerIDs <- worker
+			}()
+
+			defer func() {
+				if p := recover(); p != nil {
+					lock.Lock()
+					if failed == nil {
+						failed = p
+					}
+					lock.Unlock()
+				}
+			}()
+
+			if isFailed() {
+				return
+			}
+
+			context := newInjectorContext(r)
+			context.worker = worker
+			context.ctx = ctx
+			context.getInstanceByType(t)
+		}()
+	}
+
+	wg.Wait()
+	if failed != nil {
+		panic(failed)
+	}
+}
@@ -0,0 +1,100 @@
+package di_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+type slowService1 struct{}
+type slowService2 struct{}
+type slowService3 struct{}
+type slowConsumer struct{}
+
+func TestParallelEager(t *testing.T) {
+	var running, maxRunning int32
+	enter := func() {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+	}
+
+	consumerCreated := false
+	impls := di.NewImplements()
+	impls.AddImplement("Services", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*slowService1](binder).ToConstructor(func() *slowService1 {
+			enter()
+			return &slowService1{}
+		}).AsEagerSingleton()
+
+		di.Bind[*slowService2](binder).ToConstructor(func() *slowService2 {
+			enter()
+			return &slowService2{}
+		}).AsEagerSingleton()
+
+		di.Bind[*slowService3](binder).ToConstructor(func() *slowService3 {
+			enter()
+			return &slowService3{}
+		}).AsEagerSingleton()
+
+		di.Bind[*slowConsumer](binder).ToConstructor(func(s1 *slowService1, s2 *slowService2) *slowConsumer {
+			if s1 == nil || s2 == nil {
+				t.Error("dependencies are not created")
+			}
+			consumerCreated = true
+			return &slowConsumer{}
+		}).AsEagerSingleton()
+	}))
+
+	workers := map[int]bool{}
+	lock := sync.Mutex{}
+	impls.NewInjectorWithTrace([]string{"Services"}, func(info *di.TraceInfo) {
+		if info.TraceType == di.InstanceCreated && info.IsEager {
+			lock.Lock()
+			workers[info.Worker] = true
+			lock.Unlock()
+		}
+	}, di.WithParallelEager(3))
+
+	if !consumerCreated {
+		t.Error("consumer is not created")
+	}
+
+	if atomic.LoadInt32(&maxRunning) < 2 {
+		t.Errorf("eager singletons are not created concurrently : %d", maxRunning)
+	}
+
+	if workers[0] {
+		t.Error("worker id is not set")
+	}
+}
+
+func TestParallelEagerPanic(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddImplement("Services", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*slowService1](binder).ToConstructor(func() *slowService1 {
+			panic("can't create service")
+		}).AsEagerSingleton()
+
+		di.Bind[*slowConsumer](binder).ToConstructor(func(s1 *slowService1) *slowConsumer {
+			t.Error("consumer is created after dependency failed")
+			return &slowConsumer{}
+		}).AsEagerSingleton()
+	}))
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("panic is not propagated")
+		}
+	}()
+	impls.NewInjector([]string{"Services"}, di.WithParallelEager(2))
+}
@@ -0,0 +1,162 @@
+package di
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// placeholderResolver replaces ${name} and ${name:default} placeholders with property values.
+// ${env.NAME} is replaced with environment variable NAME if there is no env.NAME property.
+// $${ is replaced with literal ${ and placeholders of unknown properties are left as they are
+// unless strict is set
+type placeholderResolver struct {
+	lookup   func(propName string) (string, bool)
+	resolved map[string]string
+	path     []string
+	strict   bool
+}
+
+func newPlaceholderResolver(lookup func(propName string) (string, bool)) *placeholderResolver {
+	return &placeholderResolver{lookup: lookup, resolved: map[string]string{}}
+}
+
+// property returns resolved value of the property
+func (r *placeholderResolver) property(propName string) (string, bool, error) {
+	if v, ok := r.resolved[propName]; ok {
+		return v, true, nil
+	}
+
+	for i, p := range r.path {
+		if p == propName {
+			return "", false, fmt.Errorf("placeholder cycle : %s", strings.Join(append(r.path[i:], propName), " -> "))
+		}
+	}
+
+	value, ok := r.lookup(propName)
+	if !ok {
+		if envName, isEnv := strings.CutPrefix(propName, "env."); isEnv {
+			value, ok = os.LookupEnv(envName)
+		}
+		if !ok {
+			return "", false, nil
+		}
+	}
+
+	r.path = append(r.path, propName)
+	defer func() {
+		r.path = r.path[0 : len(r.path)-1]
+	}()
+
+	ret, err := r.resolve(value)
+	if err != nil {
+		return "", false, err
+	}
+	r.resolved[propName] = ret
+	return ret, true, nil
+}
+
+// resolve replaces placeholders of the value
+func (r *placeholderResolver) resolve(value string) (string, error) {
+	start := strings.Index(value, "${")
+	if start < 0 {
+		return value, nil
+	}
+
+	if start > 0 && value[start-1] == '$' {
+		rest, err := r.resolve(value[start+2:])
+		if err != nil {
+			return "", err
+		}
+		return value[:start-1] + "${" + rest, nil
+	}
+
+	// find matching brace for nested placeholder in default value
+	depth := 0
+	end := -1
+	for i := start + 2; i < len(value) && end < 0; i++ {
+		switch {
+		case value[i] == '}' && depth == 0:
+			end = i
+		case value[i] == '}':
+			depth--
+		case value[i] == '{' && value[i-1] == '$':
+			depth++
+		}
+	}
+	if end < 0 {
+		if !r.strict {
+			return value, nil
+		}
+		return "", fmt.Errorf("unclosed placeholder : %s", value)
+	}
+
+	name, defaultValue, hasDefault := strings.Cut(value[start+2:end], ":")
+
+	replaced, ok, err := r.property(name)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		if !hasDefault {
+			if r.strict {
+				return "", fmt.Errorf("property %s is not set for placeholder of %q", name, value)
+			}
+			replaced = value[start : end+1]
+		} else {
+			replaced, err = r.resolve(defaultValue)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	rest, err := r.resolve(value[end+1:])
+	if err != nil {
+		return "", err
+	}
+	return value[:start] + replaced + rest, nil
+}
+
+// resolvePlaceholders returns properties whose placeholders are replaced
+func resolvePlaceholders(props map[string]string) (map[string]string, error) {
+	resolver := newPlaceholderResolver(func(propName string) (string, bool) {
+		v, ok := props[propName]
+		return v, ok
+	})
+
+	ret := make(map[string]string, len(props))
+	for k := range props {
+		v, _, err := resolver.property(k)
+		if err != nil {
+			return nil, err
+		}
+		ret[k] = v
+	}
+	return ret, nil
+}
+
+// expandModuleNames replaces placeholders of module names.
+// a placeholder can be replaced with comma separated module names or empty string
+func expandModuleNames(moduleNames []string, props map[string]string) ([]string, error) {
+	resolver := newPlaceholderResolver(func(propName string) (string, bool) {
+		v, ok := props[propName]
+		return v, ok
+	})
+	resolver.strict = true
+
+	var ret []string
+	for _, name := range moduleNames {
+		if !strings.Contains(name, "${") {
+			ret = append(ret, name)
+			continue
+		}
+
+		expanded, err := resolver.resolve(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module name %s : %w", name, err)
+		}
+		ret = append(ret, splitList(expanded)...)
+	}
+	return ret, nil
+}
@@ -0,0 +1,134 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+func TestPlaceholderProperties(t *testing.T) {
+	t.Setenv("REGION", "eu")
+
+	impls := di.NewImplements()
+	impls.SetProperty("cache.host", "${region}.cache.local")
+	impls.SetProperty("region", "${env.REGION}")
+	impls.SetProperty("cache.kind", "${cache.type:${default.cache:memory}}")
+	impls.SetProperty("cache.url", "${cache.kind}://${cache.host}:${cache.port:6379}")
+
+	injector := impls.NewInjector(nil)
+	if v := injector.GetProperty("cache.url"); v != "memory://eu.cache.local:6379" {
+		t.Errorf("unexpected cache.url : %s", v)
+	}
+
+	injector.SetProperty("cache.url", "${cache.host}")
+	if v := injector.GetProperty("cache.url"); v != "eu.cache.local" {
+		t.Errorf("unexpected cache.url : %s", v)
+	}
+}
+
+func TestPlaceholderCycle(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		} else if r != "placeholder cycle : a -> b -> a" && r != "placeholder cycle : b -> a -> b" {
+			t.Errorf("unexpected panic : %v", r)
+		}
+	}()
+
+	impls := di.NewImplements()
+	impls.SetProperty("a", "${b}")
+	impls.SetProperty("b", "${a}")
+	impls.NewInjector(nil)
+}
+
+func TestPlaceholderModuleNames(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddImplement("eu.Cache", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"eu"})
+	}))
+	impls.AddImplement("us.Cache", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"us"})
+	}))
+	impls.SetProperty("region", "eu")
+
+	injector := impls.NewInjector([]string{"${region}.Cache", "${extra.modules:}"})
+	if injector.GetInstance((*Value1)(nil)).(Value1).Value() != "eu" {
+		t.Errorf("eu cache not binded")
+	}
+}
+
+func TestPlaceholderLiteral(t *testing.T) {
+	impls := di.NewImplements()
+	impls.SetProperty("escaped", "$${user}")
+	impls.SetProperty("greeting", "hello ${user}")
+	impls.SetProperty("unclosed", "${user")
+
+	injector := impls.NewInjector(nil)
+	if v := injector.GetProperty("escaped"); v != "${user}" {
+		t.Errorf("unexpected escaped : %s", v)
+	}
+	if v := injector.GetProperty("greeting"); v != "hello ${user}" {
+		t.Errorf("unexpected greeting : %s", v)
+	}
+	if v := injector.GetProperty("unclosed"); v != "${user" {
+		t.Errorf("unexpected unclosed : %s", v)
+	}
+
+	injector.SetProperty("tmpl", "hi ${name}, $${name}")
+	if v := injector.GetProperty("tmpl"); v != "hi ${name}, ${name}" {
+		t.Errorf("unexpected tmpl : %s", v)
+	}
+
+	injector.SetProperty("user", "guest")
+	if v := injector.GetProperty("greeting"); v != "hello guest" {
+		t.Errorf("unexpected greeting : %s", v)
+	}
+	if v := injector.GetProperty("escaped"); v != "${user}" {
+		t.Errorf("unexpected escaped : %s", v)
+	}
+}
+
+func TestPlaceholderDependentChange(t *testing.T) {
+	impls := di.NewImplements()
+	impls.SetProperty("host", "localhost")
+	impls.SetProperty("url", "http://${host}:8080")
+
+	injector := impls.NewInjector(nil)
+
+	var changed []string
+	injector.WatchProperty("url", func(oldValue string, newValue string) {
+		changed = append(changed, newValue)
+	})
+
+	injector.SetProperty("host", "example.com")
+	if v := injector.GetProperty("url"); v != "http://example.com:8080" {
+		t.Errorf("unexpected url : %s", v)
+	}
+	if len(changed) != 1 || changed[0] != "http://example.com:8080" {
+		t.Errorf("url watcher is not called : %v", changed)
+	}
+}
+
+func TestPlaceholderCycleBySet(t *testing.T) {
+	impls := di.NewImplements()
+	impls.SetProperty("a", "${b:x}")
+
+	injector := impls.NewInjector(nil)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("The code did not panic")
+			}
+		}()
+		injector.SetProperty("b", "${a}")
+	}()
+
+	if v := injector.GetProperty("a"); v != "x" {
+		t.Errorf("unexpected a : %s", v)
+	}
+	injector.SetProperty("b", "y")
+	if v := injector.GetProperty("a"); v != "y" {
+		t.Errorf("unexpected a : %s", v)
+	}
+}
@@ -0,0 +1,240 @@
+package di
+
+import (
+	"reflect"
+
+	"github.com/csgura/fp/reflectfp"
+)
+
+type memberKind int
+
+const (
+	memberProperty memberKind = iota
+	memberInstance
+	memberProvider
+	memberOption
+	memberLazy
+	memberNested
+)
+
+// memberPlan is compiled injection of a struct field
+type memberPlan struct {
+	index int
+	field reflect.StructField
+	kind  memberKind
+	tag   injectTag
+
+	// key is binding key of the field and binding is resolved binding of key. binding is nil if key is not binded
+	key     reflect.Type
+	binding *Binding
+
+	// nilOnly means the field is injected only if it is nil
+	nilOnly bool
+
+	// convert means the instance is converted to the field type
+	convert bool
+
+	// required means it panics if the instance is nil
+	required bool
+
+	// target returns name of the field for error message
+	target func() string
+}
+
+type structPlanKey struct {
+	tpe        reflect.Type
+	requireAll bool
+}
+
+type argKind int
+
+const (
+	argProperty argKind = iota
+	argProvider
+	argIn
+	argLazy
+	argJit
+	argOption
+	argInstance
+)
+
+// argPlan is compiled injection of a function argument
+type argPlan struct {
+	kind    argKind
+	tpe     reflect.Type
+	key     reflect.Type
+	binding *Binding
+
+	// tag and valueType are used for property argument
+	tag       injectTag
+	valueType reflect.Type
+}
+
+var (
+	propertyParamType = reflect.TypeOf((*propertyParam)(nil)).Elem()
+	providerParamType = reflect.TypeOf((*providerParam)(nil)).Elem()
+)
+
+// structPlan returns cached injection plan of struct type t.
+// plans are cached per injector because they have resolved bindings of the injector
+func (r *injectorImpl) structPlan(t reflect.Type, requireAll bool) []memberPlan {
+	key := structPlanKey{t, requireAll}
+	if plan, ok := r.structPlans.Load(key); ok {
+		return plan.([]memberPlan)
+	}
+
+	plan, _ := r.structPlans.LoadOrStore(key, r.compileStruct(t, requireAll))
+	return plan.([]memberPlan)
+}
+
+func (r *injectorImpl) compileStruct(t reflect.Type, requireAll bool) []memberPlan {
+	explicitInject := requireAll
+	for i := 0; i < t.NumField() && !explicitInject; i++ {
+		if hasInjectTag(t.Field(i).Tag).inject {
+			explicitInject = true
+		}
+	}
+
+	ret := []memberPlan{}
+	for i := 0; i < t.NumField(); i++ {
+		fieldType := t.Field(i)
+
+		if fieldType.Anonymous && fieldType.Type == inType {
+			continue
+		}
+
+		// unexported fields can't be set
+		if !fieldType.IsExported() {
+			continue
+		}
+
+		tag := memberTag(fieldType, requireAll)
+		m := memberPlan{index: i, field: fieldType, tag: tag, required: explicitInject && !tag.nilable}
+
+		if tag.prop != "" {
+			m.kind = memberProperty
+			target := t.String() + "." + fieldType.Name
+			m.target = func() string {
+				return target
+			}
+			ret = append(ret, m)
+			continue
+		}
+
+		injectable := explicitInject == false || tag.inject
+
+		switch fieldType.Type.Kind() {
+		case reflect.Func, reflect.Interface:
+			if !injectable {
+				continue
+			}
+			m.kind = memberInstance
+			m.key = reflect.PtrTo(fieldType.Type)
+			m.nilOnly = true
+		case reflect.Ptr:
+			if !injectable {
+				continue
+			}
+			m.kind = memberInstance
+			m.key = fieldType.Type
+			m.nilOnly = true
+		case reflect.Struct:
+			if !injectable {
+				continue
+			}
+			if reflect.PtrTo(fieldType.Type).Implements(providerParamType) {
+				m.kind = memberProvider
+			} else if valType, ok := reflectfp.MatchOption(fieldType.Type).Unapply(); ok {
+				m.kind = memberOption
+				m.key = reflect.PtrTo(valType)
+			} else if valType, ok := reflectfp.MatchLazyEval(fieldType.Type).Unapply(); ok {
+				m.kind = memberLazy
+				m.key = reflect.PtrTo(valType)
+			} else {
+				m.kind = memberNested
+			}
+		default:
+			if !tag.inject {
+				continue
+			}
+			m.kind = memberInstance
+			m.key = reflect.PtrTo(fieldType.Type)
+			m.convert = true
+		}
+
+		if m.key != nil {
+			m.binding = r.binder.providers[m.key]
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+// callPlan returns cached injection plan of arguments of function type t
+func (r *injectorImpl) callPlan(t reflect.Type) []argPlan {
+	if plan, ok := r.callPlans.Load(t); ok {
+		return plan.([]argPlan)
+	}
+
+	plan, _ := r.callPlans.LoadOrStore(t, r.compileCall(t))
+	return plan.([]argPlan)
+}
+
+func (r *injectorImpl) compileCall(ftype reflect.Type) []argPlan {
+	ret := make([]argPlan, ftype.NumIn())
+	for i := range ret {
+		argtype := ftype.In(i)
+		a := argPlan{tpe: argtype}
+
+		if reflect.PtrTo(argtype).Implements(propertyParamType) {
+			pv := reflect.New(argtype).Interface().(propertyParam)
+			a.kind = argProperty
+			a.tag = pv.propertyTag()
+			a.valueType = pv.valueType()
+			ret[i] = a
+			continue
+		}
+
+		if reflect.PtrTo(argtype).Implements(providerParamType) {
+			a.kind = argProvider
+			ret[i] = a
+			continue
+		}
+
+		if isInStruct(argtype) {
+			a.kind = argIn
+			ret[i] = a
+			continue
+		}
+
+		lazyType := reflectfp.MatchLazyEval(argtype)
+
+		optType := reflectfp.MatchOption(argtype)
+
+		bindtype := argtype
+		if optType.IsDefined() {
+			bindtype = reflect.PtrTo(optType.Get())
+		} else if lazyType.IsDefined() {
+			bindtype = reflect.PtrTo(lazyType.Get())
+		}
+
+		if bindtype.Kind() != reflect.Ptr {
+			bindtype = reflect.PtrTo(argtype)
+		}
+
+		a.key = bindtype
+		a.binding = r.binder.providers[bindtype]
+
+		if a.binding != nil && optType.IsEmpty() && lazyType.IsDefined() {
+			a.kind = argLazy
+		} else if optType.IsEmpty() && a.binding == nil && argtype.Kind() == reflect.Ptr && bindtype.Elem().Kind() == reflect.Struct {
+			a.kind = argJit
+		} else if optType.IsDefined() {
+			a.kind = argOption
+		} else {
+			a.kind = argInstance
+		}
+		ret[i] = a
+	}
+	return ret
+}
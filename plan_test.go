@@ -0,0 +1,54 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+type planTarget struct {
+	Cache benchCache `di:"inject,nilable"`
+	Repo  *benchRepo `di:"inject,nilable"`
+}
+
+type namedCache string
+
+func (r namedCache) Get(key string) string {
+	return string(r) + key
+}
+
+func TestInjectionPlanPerInjector(t *testing.T) {
+	newInjector := func(name string) di.Injector {
+		impls := di.NewImplements()
+		impls.AddBind(func(binder *di.Binder) {
+			if name != "" {
+				di.Bind[benchCache](binder).ToInstance(namedCache(name))
+			}
+		})
+		return impls.NewInjector(nil)
+	}
+
+	for _, name := range []string{"a", "b", ""} {
+		injector := newInjector(name)
+		for i := 0; i < 2; i++ {
+			target := planTarget{}
+			injector.InjectMembers(&target)
+
+			if name == "" {
+				if target.Cache != nil {
+					t.Errorf("cache of other injector is injected : %v", target.Cache)
+				}
+			} else if target.Cache == nil || target.Cache.Get("") != name {
+				t.Errorf("unexpected cache : %v", target.Cache)
+			}
+
+			if name != "" {
+				injector.InjectAndCall(func(cache benchCache) {
+					if cache.Get("") != name {
+						t.Errorf("unexpected cache argument : %v", cache)
+					}
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package di
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+)
+
+func (r *Implements) addToProfile(profile string, name string) {
+	if !slices.Contains(r.profiles[profile], name) {
+		r.profiles[profile] = append(r.profiles[profile], name)
+	}
+}
+
+// InProfiles adds the most recently added named module to profiles
+func (r *Implements) InProfiles(profiles ...string) *Implements {
+	if r.lastAdded == "" {
+		panic("InProfiles should be called after AddImplement")
+	}
+
+	for _, profile := range profiles {
+		r.addToProfile(profile, r.lastAdded)
+	}
+	return r
+}
+
+// Profiles returns sorted names of profiles
+func (r *Implements) Profiles() []string {
+	ret := make([]string, 0, len(r.profiles))
+	for profile := range r.profiles {
+		ret = append(ret, profile)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// ProfileModules returns names of modules activated by the profile in registration order
+func (r *Implements) ProfileModules(profile string) []string {
+	return slices.Clone(r.profiles[profile])
+}
+
+// ResolveProfiles returns names of modules activated by profiles and extra module names
+func (r *Implements) ResolveProfiles(profiles []string, extraModules []string) []string {
+	var ret []string
+	for _, profile := range profiles {
+		if _, exists := r.profiles[profile]; !exists {
+			panic(fmt.Sprintf("profile %s has no module", profile))
+		}
+		for _, name := range r.profiles[profile] {
+			if !slices.Contains(ret, name) {
+				ret = append(ret, name)
+			}
+		}
+	}
+
+	for _, name := range extraModules {
+		if !slices.Contains(ret, name) {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+type activeProfile struct {
+	name    string
+	modules []string
+}
+
+// withProfiles is option to report the profile of modules providing same binding
+func withProfiles(profiles []activeProfile) InjectorOption {
+	return func(options *injectorOptions) {
+		options.profiles = profiles
+	}
+}
+
+// duplicateError returns message of duplicated binding naming modules which provide it
+// and the active profile having both modules
+func (b *Binder) duplicateError(existing *Binding, binding *Binding) string {
+	if existing.module == "" || binding.module == "" {
+		return "duplicated bind for " + binding.tpe.String()
+	}
+
+	msg := fmt.Sprintf("module %s and %s provide same binding %s", existing.module, binding.module, binding.tpe)
+	for _, p := range b.profiles {
+		if slices.Contains(p.modules, existing.topModule) && slices.Contains(p.modules, binding.topModule) {
+			return msg + " in profile " + p.name
+		}
+	}
+	return msg
+}
+
+// NewInjectorForProfiles returns new Injector with modules activated by profiles and extra modules.
+// it panics if two modules provide same binding
+func (r *Implements) NewInjectorForProfiles(profiles []string, extraModules []string) Injector {
+	moduleNames := r.ResolveProfiles(profiles, extraModules)
+
+	active := make([]activeProfile, len(profiles))
+	for i, profile := range profiles {
+		active[i] = activeProfile{profile, r.profiles[profile]}
+	}
+	return r.NewInjector(moduleNames, withProfiles(active))
+}
@@ -0,0 +1,101 @@
+package di_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+func profileImplements() *di.Implements {
+	impls := di.NewImplements()
+	impls.AddImplement("TestDB", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"TestDB"})
+	})).InProfiles("test")
+
+	impls.AddImplement("ProdDB", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"ProdDB"})
+	})).InProfiles("prod")
+
+	impls.AddImplement("MemCache", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value2)(nil)).ToInstance(&ValueImpl{"MemCache"})
+	})).InProfiles("test", "prod")
+
+	impls.AddImplement("EURegion", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value3)(nil)).ToInstance(&ValueImpl{"eu"})
+	})).InProfiles("eu")
+
+	return impls
+}
+
+func TestProfiles(t *testing.T) {
+	impls := profileImplements()
+
+	if !reflect.DeepEqual(impls.Profiles(), []string{"eu", "prod", "test"}) {
+		t.Errorf("unexpected profiles : %v", impls.Profiles())
+	}
+
+	if !reflect.DeepEqual(impls.ProfileModules("prod"), []string{"ProdDB", "MemCache"}) {
+		t.Errorf("unexpected modules of prod : %v", impls.ProfileModules("prod"))
+	}
+
+	injector := impls.NewInjectorForProfiles([]string{"prod", "eu"}, nil)
+	if injector.GetInstance((*Value1)(nil)).(Value1).Value() != "ProdDB" {
+		t.Errorf("ProdDB not binded")
+	}
+
+	if injector.GetInstance((*Value3)(nil)).(Value3).Value() != "eu" {
+		t.Errorf("EURegion not binded")
+	}
+}
+
+func TestProfilesConflict(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		} else if r != "module TestDB and OtherTestDB provide same binding *di_test.Value1 in profile test" {
+			t.Errorf("unexpected panic : %v", r)
+		}
+	}()
+
+	impls := profileImplements()
+	impls.AddImplement("OtherTestDB", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"OtherTestDB"})
+	})).InProfiles("test")
+
+	impls.NewInjectorForProfiles([]string{"test"}, nil)
+}
+
+func TestProfilesConfiguredOnce(t *testing.T) {
+	impls := profileImplements()
+
+	count := 0
+	impls.AddImplement("Metrics", di.BindFunc(func(binder *di.Binder) {
+		count++
+	})).InProfiles("prod")
+
+	impls.NewInjectorForProfiles([]string{"prod"}, nil)
+	if count != 1 {
+		t.Errorf("module is configured %d times", count)
+	}
+}
+
+func TestProfilesConditionalConflict(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		} else if r != "module TestDB and CloudTestDB provide same binding *di_test.Value1 in profile test" {
+			t.Errorf("unexpected panic : %v", r)
+		}
+	}()
+
+	impls := profileImplements()
+	impls.AddImplement("CloudTestDB", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"CloudTestDB"}).When(di.PropertyEquals("db.cloud", "true"))
+	})).InProfiles("test")
+	impls.SetProperty("db.cloud", "true")
+
+	impls.NewInjectorForProfiles([]string{"test"}, nil)
+}
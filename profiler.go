@@ -0,0 +1,198 @@
+package di
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// ProfileNode is creation of an instance recorded by StartupProfiler
+type ProfileNode struct {
+	Type   reflect.Type
+	Worker int
+	Start  time.Time
+
+	// Total is elapsed time to create the instance including its dependencies
+	Total time.Duration
+
+	// Self is Total excluding time to create dependencies
+	Self time.Duration
+
+	// Failed is set if the creation didn't complete. Total and Self of failed creation are zero
+	Failed bool
+
+	Children []*ProfileNode
+}
+
+// ProfileEntry is summary of creations of a type
+type ProfileEntry struct {
+	Type  reflect.Type
+	Count int
+	Total time.Duration
+	Self  time.Duration
+}
+
+// StartupProfiler records nested creation tree of instances.
+// use Trace as TraceCallback of the injector.
+// creations are nested by worker, so instances created concurrently
+// by goroutines other than eager singleton workers may be nested wrongly
+type StartupProfiler struct {
+	lock   sync.Mutex
+	start  time.Time
+	roots  []*ProfileNode
+	stacks map[int][]*ProfileNode
+}
+
+// NewStartupProfiler returns new empty StartupProfiler
+func NewStartupProfiler() *StartupProfiler {
+	return &StartupProfiler{stacks: map[int][]*ProfileNode{}}
+}
+
+// Trace is TraceCallback recording creations
+func (r *StartupProfiler) Trace(info *TraceInfo) {
+	now := time.Now()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.start.IsZero() {
+		r.start = now
+	}
+
+	stack := r.stacks[info.Worker]
+	switch info.TraceType {
+	case InstanceWillBeCreated:
+		// the top of the stack should be the referer. creations above it failed without InstanceCreated
+		for len(stack) > 0 && stack[len(stack)-1].Type != info.Referer {
+			stack[len(stack)-1].Failed = true
+			stack = stack[:len(stack)-1]
+		}
+
+		node := &ProfileNode{Type: info.RequestedType, Worker: info.Worker, Start: now}
+		if len(stack) > 0 {
+			parent := stack[len(stack)-1]
+			parent.Children = append(parent.Children, node)
+		} else {
+			r.roots = append(r.roots, node)
+		}
+		r.stacks[info.Worker] = append(stack, node)
+
+	case InstanceCreated:
+		// creations above the created one failed, so they are dropped from the stack
+		for i := len(stack) - 1; i >= 0; i-- {
+			if node := stack[i]; node.Type == info.RequestedType {
+				for _, failed := range stack[i+1:] {
+					failed.Failed = true
+				}
+				node.Total = info.ElapsedTime
+				node.Self = node.Total
+				for _, child := range node.Children {
+					node.Self -= child.Total
+				}
+				r.stacks[info.Worker] = stack[:i]
+				break
+			}
+		}
+	}
+}
+
+// Roots returns creations which are not requested by other creation
+func (r *StartupProfiler) Roots() []*ProfileNode {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]*ProfileNode(nil), r.roots...)
+}
+
+// Entries returns summary of creations per type sorted by self time
+func (r *StartupProfiler) Entries() []ProfileEntry {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	entries := map[reflect.Type]*ProfileEntry{}
+	var visit func(nodes []*ProfileNode)
+	visit = func(nodes []*ProfileNode) {
+		for _, node := range nodes {
+			e := entries[node.Type]
+			if e == nil {
+				e = &ProfileEntry{Type: node.Type}
+				entries[node.Type] = e
+			}
+			e.Count++
+			e.Total += node.Total
+			e.Self += node.Self
+			visit(node.Children)
+		}
+	}
+	visit(r.roots)
+
+	ret := make([]ProfileEntry, 0, len(entries))
+	for _, e := range entries {
+		ret = append(ret, *e)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Self != ret[j].Self {
+			return ret[i].Self > ret[j].Self
+		}
+		return ret[i].Type.String() < ret[j].Type.String()
+	})
+	return ret
+}
+
+// Report writes top n types which take longest self time. n <= 0 means all types
+func (r *StartupProfiler) Report(w io.Writer, n int) error {
+	entries := r.Entries()
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tCOUNT\tSELF\tTOTAL")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", e.Type, e.Count, e.Self, e.Total)
+	}
+	return tw.Flush()
+}
+
+type chromeTraceEvent struct {
+	Name  string `json:"name"`
+	Cat   string `json:"cat"`
+	Phase string `json:"ph"`
+	Ts    int64  `json:"ts"`
+	Dur   int64  `json:"dur"`
+	Pid   int    `json:"pid"`
+	Tid   int    `json:"tid"`
+}
+
+// WriteChromeTrace writes creations as Chrome trace_event JSON.
+// it can be opened by chrome://tracing or https://ui.perfetto.dev
+func (r *StartupProfiler) WriteChromeTrace(w io.Writer) error {
+	r.lock.Lock()
+	events := []chromeTraceEvent{}
+	var visit func(nodes []*ProfileNode)
+	visit = func(nodes []*ProfileNode) {
+		for _, node := range nodes {
+			events = append(events, chromeTraceEvent{
+				Name:  node.Type.String(),
+				Cat:   "di",
+				Phase: "X",
+				Ts:    node.Start.Sub(r.start).Microseconds(),
+				Dur:   node.Total.Microseconds(),
+				Pid:   1,
+				Tid:   node.Worker,
+			})
+			visit(node.Children)
+		}
+	}
+	visit(r.roots)
+	r.lock.Unlock()
+
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"traceEvents":     events,
+		"displayTimeUnit": "ms",
+	})
+}
@@ -0,0 +1,114 @@
+package di_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+type profiledRepo struct{}
+type profiledService struct{}
+
+func TestStartupProfiler(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddImplement("Service", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*profiledRepo](binder).ToConstructor(func() *profiledRepo {
+			time.Sleep(20 * time.Millisecond)
+			return &profiledRepo{}
+		})
+
+		di.Bind[*profiledService](binder).ToConstructor(func(repo *profiledRepo) *profiledService {
+			time.Sleep(5 * time.Millisecond)
+			return &profiledService{}
+		}).AsEagerSingleton()
+	}))
+
+	profiler := di.NewStartupProfiler()
+	impls.NewInjectorWithTrace([]string{"Service"}, profiler.Trace)
+
+	roots := profiler.Roots()
+	if len(roots) != 1 || roots[0].Type.String() != "*di_test.profiledService" {
+		t.Fatalf("unexpected roots : %v", roots)
+	}
+
+	service := roots[0]
+	if len(service.Children) != 1 || service.Children[0].Type.String() != "*di_test.profiledRepo" {
+		t.Fatalf("unexpected children : %v", service.Children)
+	}
+
+	repo := service.Children[0]
+	if service.Self != service.Total-repo.Total || service.Self >= repo.Self {
+		t.Errorf("unexpected self time : service %s, repo %s", service.Self, repo.Self)
+	}
+
+	report := bytes.Buffer{}
+	profiler.Report(&report, 1)
+	if !strings.Contains(report.String(), "*di_test.profiledRepo") || strings.Contains(report.String(), "*di_test.profiledService") {
+		t.Errorf("unexpected report : \n%s", report.String())
+	}
+
+	out := bytes.Buffer{}
+	if err := profiler.WriteChromeTrace(&out); err != nil {
+		t.Fatal(err)
+	}
+
+	var trace struct {
+		TraceEvents []struct {
+			Name  string `json:"name"`
+			Phase string `json:"ph"`
+			Dur   int64  `json:"dur"`
+		} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &trace); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(trace.TraceEvents) != 2 || trace.TraceEvents[1].Name != "*di_test.profiledRepo" || trace.TraceEvents[1].Dur < 20000 {
+		t.Errorf("unexpected trace : %s", out.String())
+	}
+}
+
+func TestStartupProfilerFailedCreation(t *testing.T) {
+	calls := 0
+	impls := di.NewImplements()
+	impls.AddImplement("Service", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*profiledRepo](binder).ToProvider(func(injector di.Injector) *profiledRepo {
+			calls++
+			if calls == 1 {
+				panic(errors.New("repo is not ready"))
+			}
+			return &profiledRepo{}
+		})
+
+		di.Bind[*profiledService](binder).ToConstructor(func(repo *profiledRepo) *profiledService {
+			return &profiledService{}
+		})
+	}))
+
+	profiler := di.NewStartupProfiler()
+	injector := impls.NewInjectorWithTrace([]string{"Service"}, profiler.Trace)
+
+	if _, err := di.GetInstanceCtx[*profiledService](context.Background(), injector); err == nil {
+		t.Fatal("error is not returned")
+	}
+	di.GetInstance[*profiledService](injector)
+
+	roots := profiler.Roots()
+	if len(roots) != 2 {
+		t.Fatalf("unexpected roots : %v", roots)
+	}
+
+	if !roots[0].Failed || len(roots[0].Children) != 1 || !roots[0].Children[0].Failed {
+		t.Errorf("failed creation is not recorded : %v", roots[0])
+	}
+
+	if roots[1].Failed || len(roots[1].Children) != 1 || roots[1].Children[0].Failed {
+		t.Errorf("unexpected creation : %v", roots[1])
+	}
+}
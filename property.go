@@ -0,0 +1,323 @@
+package di
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Property is a name and value pair of property
+type Property struct {
+	Name  string
+	Value string
+
+	// Source is name of PropertySource which supplied the value
+	Source string
+}
+
+// PropertyWatcher is called when value of the property is changed
+type PropertyWatcher func(oldValue string, newValue string)
+
+// setPropertySourceName is name of source for properties set by Injector.SetProperty
+const setPropertySourceName = "Injector.SetProperty"
+
+type propertyWatch struct {
+	propName string
+	watcher  PropertyWatcher
+}
+
+// matches returns whether the property name matches to watched name or pattern
+func (r *propertyWatch) matches(propName string) bool {
+	if r.propName == propName {
+		return true
+	}
+	matched, _ := path.Match(r.propName, propName)
+	return matched
+}
+
+type propertyChange struct {
+	watch    *propertyWatch
+	oldValue string
+	newValue string
+}
+
+// propertyStore is multi thread safe property map.
+// props are resolved values of raw values, and they are resolved again when any property is set
+// so that a value having placeholder follows changes of the referred property
+type propertyStore struct {
+	lock    sync.RWMutex
+	raw     map[string]string
+	props   map[string]string
+	sources map[string]string
+	watches []*propertyWatch
+
+	// pending are notifications not delivered yet and delivering is set while deliverer goroutine delivers them.
+	// notifications are delivered in order of changes by one goroutine at a time.
+	// queued and delivered are number of notifications, and delivered is broadcasted by cond
+	pending    []propertyChange
+	delivering bool
+	deliverer  uint64
+	queued     uint64
+	delivered  uint64
+	cond       *sync.Cond
+}
+
+// newPropertyStore returns store of raw property values. it returns error if placeholders have cycle
+func newPropertyStore(raw map[string]string, sources map[string]string) (*propertyStore, error) {
+	props, err := resolvePlaceholders(raw)
+	if err != nil {
+		return nil, err
+	}
+	ret := &propertyStore{raw: maps.Clone(raw), props: props, sources: maps.Clone(sources)}
+	ret.cond = sync.NewCond(&ret.lock)
+	return ret, nil
+}
+
+func (r *propertyStore) get(propName string) string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.props[propName]
+}
+
+func (r *propertyStore) lookup(propName string) (string, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	v, ok := r.props[propName]
+	return v, ok
+}
+
+func (r *propertyStore) setAll(props map[string]string) {
+	r.lock.Lock()
+	prevRaw := r.raw
+	r.raw = maps.Clone(r.raw)
+	maps.Copy(r.raw, props)
+
+	resolved, err := resolvePlaceholders(r.raw)
+	if err != nil {
+		r.raw = prevRaw
+		r.lock.Unlock()
+		panic(err.Error())
+	}
+
+	for name := range props {
+		r.sources[name] = setPropertySourceName
+	}
+
+	names := make([]string, 0, len(resolved))
+	for k := range resolved {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		oldValue := r.props[name]
+		newValue := resolved[name]
+		if oldValue != newValue {
+			for _, w := range r.watches {
+				if w.matches(name) {
+					r.pending = append(r.pending, propertyChange{w, oldValue, newValue})
+					r.queued++
+				}
+			}
+		}
+	}
+	r.props = resolved
+
+	// it returns after the changes are delivered. if other goroutine is delivering, it waits for the goroutine.
+	// but if a watcher sets property, the changes are delivered after the watcher returns
+	target := r.queued
+	if r.delivered < target {
+		gid := goroutineID()
+		for r.delivered < target {
+			if !r.delivering {
+				r.delivering = true
+				r.deliverer = gid
+				r.lock.Unlock()
+				r.deliver()
+				r.lock.Lock()
+				continue
+			}
+			if r.deliverer == gid {
+				break
+			}
+			r.cond.Wait()
+		}
+	}
+	r.lock.Unlock()
+}
+
+// deliver calls watchers of pending changes without lock so that watchers can access and set properties
+func (r *propertyStore) deliver() {
+	for {
+		r.lock.Lock()
+		if len(r.pending) == 0 {
+			r.delivering = false
+			r.cond.Broadcast()
+			r.lock.Unlock()
+			return
+		}
+		c := r.pending[0]
+		r.pending = r.pending[1:]
+		r.lock.Unlock()
+
+		r.callWatcher(c)
+	}
+}
+
+// callWatcher delivers a change. if the watcher panics, it stops delivering and
+// the rest of changes are delivered by other waiting goroutine or next change
+func (r *propertyStore) callWatcher(c propertyChange) {
+	panicked := true
+	defer func() {
+		r.lock.Lock()
+		r.delivered++
+		if panicked {
+			r.delivering = false
+		}
+		r.cond.Broadcast()
+		r.lock.Unlock()
+	}()
+	c.watch.watcher(c.oldValue, c.newValue)
+	panicked = false
+}
+
+// goroutineID returns id of current goroutine parsed from its stack.
+// it is used to find that a watcher sets property in the goroutine delivering changes
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	s := strings.TrimPrefix(string(buf[:n]), "goroutine ")
+	if i := strings.IndexByte(s, ' '); i > 0 {
+		s = s[:i]
+	}
+	id, _ := strconv.ParseUint(s, 10, 64)
+	return id
+}
+
+func (r *propertyStore) watch(propName string, watcher PropertyWatcher) func() {
+	w := &propertyWatch{propName, watcher}
+
+	r.lock.Lock()
+	r.watches = append(r.watches, w)
+	r.lock.Unlock()
+
+	return func() {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		for i, v := range r.watches {
+			if v == w {
+				r.watches = append(r.watches[:i:i], r.watches[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// snapshot returns properties sorted by name
+func (r *propertyStore) snapshot() []Property {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	ret := make([]Property, 0, len(r.props))
+	for k, v := range r.props {
+		ret = append(ret, Property{k, v, r.sources[k]})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parsePropertyValue converts property value to the type.
+// slice values are comma separated
+func parsePropertyValue(t reflect.Type, value string) (reflect.Value, error) {
+	ret := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.String:
+		ret.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return ret, err
+		}
+		ret.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if t == durationType {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return ret, err
+			}
+			ret.SetInt(int64(d))
+		} else {
+			i, err := strconv.ParseInt(value, 0, t.Bits())
+			if err != nil {
+				return ret, err
+			}
+			ret.SetInt(i)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 0, t.Bits())
+		if err != nil {
+			return ret, err
+		}
+		ret.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, t.Bits())
+		if err != nil {
+			return ret, err
+		}
+		ret.SetFloat(f)
+	case reflect.Slice:
+		var list []string
+		if value != "" {
+			list = strings.Split(value, ",")
+		}
+		ret = reflect.MakeSlice(t, len(list), len(list))
+		for i, v := range list {
+			ev, err := parsePropertyValue(t.Elem(), strings.TrimSpace(v))
+			if err != nil {
+				return ret, err
+			}
+			ret.Index(i).Set(ev)
+		}
+	default:
+		return ret, fmt.Errorf("unsupported property type %s", t)
+	}
+	return ret, nil
+}
+
+// propertyValue returns converted property value of the tag.
+// it panics if the property is not set and has no default, or it can't be converted.
+// target describes where the value is injected. it is called only when it panics
+func (r *injectorContext) propertyValue(tag injectTag, t reflect.Type, target func() string) reflect.Value {
+	value, ok := r.injector.props.lookup(tag.prop)
+	if !ok {
+		if !tag.hasDefault {
+			panic(fmt.Sprintf("property %s is not set. So Can't Inject to %s", tag.prop, target()))
+		}
+		value = tag.defaultValue
+	}
+
+	ret, err := parsePropertyValue(t, value)
+	if err != nil {
+		panic(fmt.Sprintf("property %s=%q can't be converted to %s. So Can't Inject to %s : %v", tag.prop, value, t, target(), err))
+	}
+	return ret
+}
+
+// propertyParam is implemented by Prop
+type propertyParam interface {
+	propertyTag() injectTag
+	valueType() reflect.Type
+	setValue(v reflect.Value)
+}
@@ -0,0 +1,213 @@
+package di_test
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+func TestWatchProperty(t *testing.T) {
+	injector := di.CreateInjector()
+
+	var changes []string
+	cancel := injector.WatchProperty("feature.enabled", func(oldValue, newValue string) {
+		changes = append(changes, oldValue+"->"+newValue)
+	})
+
+	injector.SetProperty("feature.enabled", "true")
+	injector.SetProperty("feature.enabled", "true")
+	injector.SetProperties(map[string]string{"feature.enabled": "false", "other": "value"})
+
+	cancel()
+	injector.SetProperty("feature.enabled", "true")
+
+	if !reflect.DeepEqual(changes, []string{"->true", "true->false"}) {
+		t.Errorf("unexpected changes : %v", changes)
+	}
+
+	expected := []di.Property{{"feature.enabled", "true", "Injector.SetProperty"}, {"other", "value", "Injector.SetProperty"}}
+	if !reflect.DeepEqual(injector.Properties(), expected) {
+		t.Errorf("unexpected properties : %v", injector.Properties())
+	}
+}
+
+func TestPropertyRace(t *testing.T) {
+	injector := di.CreateInjector()
+	injector.WatchProperty("counter", func(oldValue, newValue string) {
+		injector.GetProperty("counter")
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			injector.SetProperty("counter", strconv.Itoa(i))
+			injector.GetProperty("counter")
+			injector.Properties()
+		}(i)
+	}
+	wg.Wait()
+}
+
+type dbConfig struct {
+	URL      string        `di:"prop=db.url,default=localhost"`
+	PoolSize int           `di:"prop=db.pool.size"`
+	Debug    bool          `di:"prop=db.debug,default=false"`
+	Timeout  time.Duration `di:"prop=db.timeout,default=3s"`
+	Hosts    []string      `di:"prop=db.hosts,default=a,b"`
+	Value    Value1        `di:"inject"`
+}
+
+func TestInjectPropertyMembers(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddBind(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"Value1"})
+	})
+	impls.SetProperty("db.pool.size", "10")
+	impls.SetProperty("db.timeout", "1m")
+
+	injector := impls.NewInjector(nil)
+
+	cfg := dbConfig{}
+	injector.InjectMembers(&cfg)
+
+	expected := dbConfig{"localhost", 10, false, time.Minute, []string{"a", "b"}, cfg.Value}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("unexpected config : %v", cfg)
+	}
+
+	if cfg.Value == nil {
+		t.Errorf("Value not injected")
+	}
+}
+
+func TestInjectPropertyMissing(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		} else if r != "property db.pool.size is not set. So Can't Inject to di_test.dbConfig.PoolSize" {
+			t.Errorf("unexpected panic : %v", r)
+		}
+	}()
+
+	injector := di.CreateInjector()
+	injector.InjectMembers(&dbConfig{})
+}
+
+func TestInjectPropertyInvalid(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+
+	injector := di.CreateInjector()
+	injector.SetProperty("db.pool.size", "ten")
+	injector.InjectMembers(&dbConfig{})
+}
+
+type poolSize struct{}
+
+func (poolSize) PropertyName() string {
+	return "db.pool.size"
+}
+
+type dbURL struct{}
+
+func (dbURL) PropertyName() string {
+	return "db.url"
+}
+
+func (dbURL) PropertyDefault() string {
+	return "localhost"
+}
+
+func TestInjectPropertyArgument(t *testing.T) {
+	injector := di.CreateInjector()
+	injector.SetProperty("db.pool.size", "20")
+
+	ret := injector.InjectAndCall(func(size di.Prop[int, poolSize], url di.Prop[string, dbURL]) string {
+		return fmt.Sprintf("%s:%d", url.Get(), size.Get())
+	})
+
+	if ret != "localhost:20" {
+		t.Errorf("unexpected result : %v", ret)
+	}
+}
+
+func TestWatchPropertyReentrant(t *testing.T) {
+	injector := di.CreateInjector()
+
+	var changes []string
+	injector.WatchProperty("a", func(oldValue, newValue string) {
+		changes = append(changes, "a="+newValue)
+		injector.SetProperty("b", newValue+"!")
+	})
+	injector.WatchProperty("b", func(oldValue, newValue string) {
+		changes = append(changes, "b="+newValue)
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		injector.SetProperty("a", "1")
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("SetProperty in watcher is blocked")
+	}
+
+	if !reflect.DeepEqual(changes, []string{"a=1", "b=1!"}) {
+		t.Errorf("unexpected changes : %v", changes)
+	}
+	if injector.GetProperty("b") != "1!" {
+		t.Errorf("b = %s", injector.GetProperty("b"))
+	}
+}
+
+func TestSetPropertyWaitsDelivery(t *testing.T) {
+	injector := di.NewImplements().NewInjector(nil)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	injector.WatchProperty("a", func(oldValue, newValue string) {
+		close(started)
+		<-release
+	})
+
+	var lock sync.Mutex
+	delivered := false
+	injector.WatchProperty("b", func(oldValue, newValue string) {
+		lock.Lock()
+		delivered = true
+		lock.Unlock()
+	})
+
+	go injector.SetProperty("a", "1")
+	<-started
+
+	done := make(chan bool)
+	go func() {
+		injector.SetProperty("b", "1")
+		lock.Lock()
+		defer lock.Unlock()
+		done <- delivered
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+
+	if !<-done {
+		t.Error("SetProperty returned before its change is delivered")
+	}
+}
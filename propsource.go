@@ -0,0 +1,174 @@
+package di
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PropertySource provides properties to the injector
+type PropertySource interface {
+	// Name is used to show which source supplied a property
+	Name() string
+	Properties() (map[string]string, error)
+}
+
+type mapSource struct {
+	name  string
+	props map[string]string
+}
+
+func (r *mapSource) Name() string {
+	return r.name
+}
+
+func (r *mapSource) Properties() (map[string]string, error) {
+	return r.props, nil
+}
+
+// MapSource returns PropertySource which provides properties of the map
+func MapSource(name string, props map[string]string) PropertySource {
+	return &mapSource{name, props}
+}
+
+type envSource struct {
+	prefix  string
+	mapping func(envName string) string
+}
+
+func (r *envSource) Name() string {
+	return "env"
+}
+
+func (r *envSource) Properties() (map[string]string, error) {
+	ret := map[string]string{}
+	for _, kv := range os.Environ() {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, r.prefix) {
+			continue
+		}
+		if propName := r.mapping(strings.TrimPrefix(name, r.prefix)); propName != "" {
+			ret[propName] = value
+		}
+	}
+	return ret, nil
+}
+
+// EnvPropertyName converts environment variable name to property name. DB_POOL_SIZE is converted to db.pool.size
+func EnvPropertyName(envName string) string {
+	return strings.ToLower(strings.ReplaceAll(envName, "_", "."))
+}
+
+// EnvSource returns PropertySource which provides environment variables having the prefix.
+// the prefix is removed and the rest is converted to property name by mapping.
+// if mapping is nil, EnvPropertyName is used and variables mapped to empty name are ignored
+func EnvSource(prefix string, mapping func(envName string) string) PropertySource {
+	if mapping == nil {
+		mapping = EnvPropertyName
+	}
+	return &envSource{prefix, mapping}
+}
+
+type flagSource struct {
+	flagSet *flag.FlagSet
+}
+
+func (r *flagSource) Name() string {
+	return "flags"
+}
+
+func (r *flagSource) Properties() (map[string]string, error) {
+	ret := map[string]string{}
+	r.flagSet.Visit(func(f *flag.Flag) {
+		ret[f.Name] = f.Value.String()
+	})
+	return ret, nil
+}
+
+// FlagSource returns PropertySource which provides flags set in command line.
+// flags not set are not provided so that default values of flags don't override other sources
+func FlagSource(flagSet *flag.FlagSet) PropertySource {
+	return &flagSource{flagSet}
+}
+
+type fileSource struct {
+	path   string
+	isJSON bool
+}
+
+func (r *fileSource) Name() string {
+	return "file:" + r.path
+}
+
+func (r *fileSource) Properties() (map[string]string, error) {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if r.isJSON {
+		var obj map[string]interface{}
+		if err := json.NewDecoder(f).Decode(&obj); err != nil {
+			return nil, fmt.Errorf("%s : %w", r.path, err)
+		}
+		ret := map[string]string{}
+		flattenProperties("", obj, ret)
+		return ret, nil
+	}
+
+	ret, err := parseProperties(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s : %w", r.path, err)
+	}
+	return ret, nil
+}
+
+// JSONFileSource returns PropertySource which provides properties of json file.
+// nested objects are converted to dot separated property names
+func JSONFileSource(path string) PropertySource {
+	return &fileSource{path, true}
+}
+
+// PropertiesFileSource returns PropertySource which provides properties of .properties file
+func PropertiesFileSource(path string) PropertySource {
+	return &fileSource{path, false}
+}
+
+// AddPropertySource adds source of properties of injector.
+// sources added later override properties of sources added before,
+// and properties set by SetProperty override all sources
+func (r *Implements) AddPropertySource(source PropertySource) *Implements {
+	r.propSources = append(r.propSources, source)
+	return r
+}
+
+// implementsSourceName is name of source for properties set by Implements.SetProperty
+const implementsSourceName = "Implements.SetProperty"
+
+// loadProperties returns store of merged properties of sources.
+// placeholders of property values are replaced
+func (r *Implements) loadProperties() (*propertyStore, error) {
+	props := map[string]string{}
+	sources := map[string]string{}
+
+	add := func(name string, values map[string]string) {
+		for k, v := range values {
+			props[k] = v
+			sources[k] = name
+		}
+	}
+
+	for _, source := range r.propSources {
+		values, err := source.Properties()
+		if err != nil {
+			return nil, fmt.Errorf("can't load properties from %s : %w", source.Name(), err)
+		}
+		add(source.Name(), values)
+	}
+	add(implementsSourceName, r.props)
+
+	return newPropertyStore(props, sources)
+}
@@ -0,0 +1,62 @@
+package di_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+func TestPropertySources(t *testing.T) {
+	dir := t.TempDir()
+
+	jsonPath := filepath.Join(dir, "application.json")
+	os.WriteFile(jsonPath, []byte(`{ "db" : { "url" : "json", "pool" : { "size" : 5 } }, "cache" : { "kind" : "memory" } }`), 0644)
+
+	propsPath := filepath.Join(dir, "application.properties")
+	os.WriteFile(propsPath, []byte("db.url=properties\nlog.level=info\n"), 0644)
+
+	t.Setenv("TESTAPP_DB_URL", "env")
+	t.Setenv("TESTAPP_REGION", "eu")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("db.url", "default", "")
+	fs.String("log.level", "debug", "")
+	fs.Parse([]string{"-db.url=flag"})
+
+	impls := di.NewImplements()
+	impls.AddPropertySource(di.JSONFileSource(jsonPath))
+	impls.AddPropertySource(di.PropertiesFileSource(propsPath))
+	impls.AddPropertySource(di.EnvSource("TESTAPP_", nil))
+	impls.AddPropertySource(di.FlagSource(fs))
+	impls.SetProperty("cache.kind", "redis")
+
+	injector := impls.NewInjector(nil)
+
+	expected := []di.Property{
+		{"cache.kind", "redis", "Implements.SetProperty"},
+		{"db.pool.size", "5", "file:" + jsonPath},
+		{"db.url", "flag", "flags"},
+		{"log.level", "info", "file:" + propsPath},
+		{"region", "eu", "env"},
+	}
+	if !reflect.DeepEqual(injector.Properties(), expected) {
+		t.Errorf("unexpected properties : %v", injector.Properties())
+	}
+}
+
+func TestPropertySourceError(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		}
+	}()
+
+	impls := di.NewImplements()
+	impls.AddPropertySource(di.JSONFileSource(filepath.Join(t.TempDir(), "missing.json")))
+	impls.NewInjector(nil)
+}
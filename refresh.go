@@ -0,0 +1,114 @@
+package di
+
+import (
+	"io"
+	"log/slog"
+	"reflect"
+	"sync"
+)
+
+// Refreshable makes the singleton recreated when properties matching to the patterns are changed.
+// patterns are like db.* and old instance is closed if it implements io.Closer.
+// consumers should use Provider to get new instance
+func (b *Binding) Refreshable(patterns ...string) *Binding {
+	if !b.isSingleton {
+		panic("Refreshable binding should be singleton : " + b.tpe.String())
+	}
+
+	b.refreshPatterns = append(b.refreshPatterns, patterns...)
+	return b
+}
+
+// refresh makes created instance stale so that it is created again on next access.
+// if the instance is being created, for example its provider sets a property, refresh doesn't wait
+// and the instance is dropped by next access. it returns error of closing old instance
+func (b *Binding) refresh() error {
+	b.refreshGen.Add(1)
+	b.ready.Store(nil)
+
+	if !b.refreshLock.TryLock() {
+		return nil
+	}
+	old := b.dropStale()
+	b.refreshLock.Unlock()
+
+	return closeInstance(old)
+}
+
+// dropStale drops the instance if it is created before refresh and returns it. refreshLock should be locked
+func (b *Binding) dropStale() interface{} {
+	gen := b.refreshGen.Load()
+	if b.builtGen.Load() == gen {
+		return nil
+	}
+
+	old := b.instance
+	b.instance = nil
+	b.singletonOnce = sync.Once{}
+	b.created.Store(false)
+	b.builtGen.Store(gen)
+	return old
+}
+
+// lockFresh drops stale instance and read locks the binding while the instance is created or returned
+func (r *injectorContext) lockFresh(b *Binding) {
+	if b.builtGen.Load() != b.refreshGen.Load() {
+		b.refreshLock.Lock()
+		old := b.dropStale()
+		b.refreshLock.Unlock()
+
+		if err := closeInstance(old); err != nil && r.injector.logger != nil {
+			r.injector.logger.Error("closing refreshed singleton failed", slog.String("type", b.tpe.String()), slog.Any("error", err))
+		}
+	}
+	b.refreshLock.RLock()
+}
+
+func closeInstance(instance interface{}) error {
+	if c, ok := instance.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// watchRefreshables registers property watchers of refreshable bindings
+func (r *injectorImpl) watchRefreshables() {
+	for _, p := range r.binder.providers {
+		binding := p
+		for _, pattern := range binding.refreshPatterns {
+			pattern := pattern
+			r.props.watch(pattern, func(oldValue, newValue string) {
+				err := binding.refresh()
+				if r.logger == nil {
+					return
+				}
+				if err != nil {
+					r.logger.Error("closing refreshed singleton failed", slog.String("type", binding.tpe.String()), slog.Any("error", err))
+				} else {
+					r.logger.Info("singleton refreshed", slog.String("type", binding.tpe.String()), slog.String("pattern", pattern))
+				}
+			})
+		}
+	}
+}
+
+// providerParam is implemented by Provider
+type providerParam interface {
+	bindType() reflect.Type
+	setGetter(getter func() interface{})
+}
+
+// providerValue returns Provider which gets instance from the injector if t is type of Provider
+func (r *injectorContext) providerValue(t reflect.Type) (reflect.Value, bool) {
+	pv, ok := reflect.New(t).Interface().(providerParam)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	injector := r.injector
+	bindType := pv.bindType()
+	pv.setGetter(func() interface{} {
+		return injector.getInstanceByType(bindType)
+	})
+	return reflect.ValueOf(pv).Elem(), true
+}
@@ -0,0 +1,102 @@
+package di_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+type dbConnection struct {
+	url    string
+	closed bool
+}
+
+func (r *dbConnection) Close() error {
+	r.closed = true
+	return nil
+}
+
+type repository struct {
+	conn di.Provider[*dbConnection]
+}
+
+type repositoryMembers struct {
+	Conn di.Provider[*dbConnection] `di:"inject"`
+}
+
+func TestRefreshable(t *testing.T) {
+	created := 0
+
+	impls := di.NewImplements()
+	impls.AddBind(func(binder *di.Binder) {
+		di.Bind[*dbConnection](binder).ToProvider(func(injector di.Injector) *dbConnection {
+			created++
+			return &dbConnection{url: injector.GetProperty("db.url")}
+		}).Refreshable("db.*")
+
+		di.BindConstructor[*repository](binder, func(conn di.Provider[*dbConnection]) *repository {
+			return &repository{conn}
+		})
+	})
+	impls.SetProperty("db.url", "first")
+
+	injector := impls.NewInjector(nil)
+	repo := di.GetInstance[*repository](injector)
+
+	first := repo.conn.Get()
+	if first.url != "first" || repo.conn.Get() != first {
+		t.Errorf("unexpected connection : %v", first)
+	}
+
+	injector.SetProperty("other", "value")
+	injector.SetProperty("db.url", "second")
+
+	if !first.closed {
+		t.Errorf("old connection is not closed")
+	}
+
+	members := repositoryMembers{}
+	injector.InjectMembers(&members)
+
+	second := members.Conn.Get()
+	if second.url != "second" || repo.conn.Get() != second {
+		t.Errorf("connection is not refreshed : %v", second)
+	}
+
+	if created != 2 {
+		t.Errorf("connection created %d times", created)
+	}
+}
+
+func TestRefreshableSetByProvider(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddBind(func(binder *di.Binder) {
+		di.Bind[*dbConnection](binder).ToProvider(func(injector di.Injector) *dbConnection {
+			injector.SetProperty("db.lastOpened", time.Now().String())
+			return &dbConnection{url: injector.GetProperty("db.url")}
+		}).Refreshable("db.*")
+	})
+	impls.SetProperty("db.url", "first")
+
+	injector := impls.NewInjector(nil)
+
+	done := make(chan *dbConnection, 1)
+	go func() {
+		done <- di.GetInstance[*dbConnection](injector)
+	}()
+
+	select {
+	case conn := <-done:
+		if conn.url != "first" {
+			t.Errorf("unexpected connection : %v", conn)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("creation of refreshable singleton is blocked by refresh")
+	}
+
+	injector.SetProperty("db.url", "second")
+	if conn := di.GetInstance[*dbConnection](injector); conn.url != "second" {
+		t.Errorf("unexpected connection : %v", conn)
+	}
+}
@@ -0,0 +1,60 @@
+package di
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+var defaultRegistry = struct {
+	lock         sync.Mutex
+	implements   *Implements
+	registeredBy map[string]string
+}{
+	implements:   NewImplements(),
+	registeredBy: make(map[string]string),
+}
+
+// callerPackage returns package path of the function calling the caller of callerPackage
+func callerPackage() string {
+	pc, _, _, ok := runtime.Caller(2)
+	if !ok {
+		return "unknown"
+	}
+
+	name := runtime.FuncForPC(pc).Name()
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// Register adds named module to default implements with profiles.
+// it is usually called in init function of module package so that importing the package makes the module selectable.
+// it panics if the name is already registered
+func Register(name string, module AbstractModule, profiles ...string) {
+	pkg := callerPackage()
+
+	defaultRegistry.lock.Lock()
+	defer defaultRegistry.lock.Unlock()
+
+	if by, exists := defaultRegistry.registeredBy[name]; exists {
+		panic(fmt.Sprintf("module %s is registered by both %s and %s", name, by, pkg))
+	}
+
+	defaultRegistry.registeredBy[name] = pkg
+	defaultRegistry.implements.AddImplement(name, module)
+	if len(profiles) > 0 {
+		defaultRegistry.implements.InProfiles(profiles...)
+	}
+}
+
+// DefaultImplements returns snapshot of modules registered by Register
+func DefaultImplements() *Implements {
+	defaultRegistry.lock.Lock()
+	defer defaultRegistry.lock.Unlock()
+
+	return defaultRegistry.implements.Clone()
+}
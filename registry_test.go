@@ -0,0 +1,43 @@
+package di_test
+
+import (
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+func init() {
+	di.Register("registry.Value1", di.BindFunc(func(binder *di.Binder) {
+		binder.Bind((*Value1)(nil)).ToInstance(&ValueImpl{"registry"})
+	}), "registry")
+}
+
+func TestRegister(t *testing.T) {
+	impls := di.DefaultImplements()
+	if !impls.HasImplement("registry.Value1") {
+		t.Fatal("module not registered")
+	}
+
+	impls.AddImplement("local", di.BindFunc(func(binder *di.Binder) {}))
+	if di.DefaultImplements().HasImplement("local") {
+		t.Errorf("DefaultImplements is not snapshot")
+	}
+
+	injector := impls.NewInjectorForProfiles([]string{"registry"}, nil)
+	if injector.GetInstance((*Value1)(nil)).(Value1).Value() != "registry" {
+		t.Errorf("Value1 not binded")
+	}
+}
+
+func TestRegisterCollision(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Errorf("The code did not panic")
+		} else if r != "module registry.Value1 is registered by both github.com/csgura/di_test and github.com/csgura/di_test" {
+			t.Errorf("unexpected panic : %v", r)
+		}
+	}()
+
+	di.Register("registry.Value1", di.BindFunc(func(binder *di.Binder) {}))
+}
@@ -0,0 +1,109 @@
+package di
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// RetryPolicy is policy to call failing provider again.
+// provider fails if it panics or ToProviderCtx provider returns error
+type RetryPolicy struct {
+	// Attempts is max number of calls including the first call
+	Attempts int
+
+	// Backoff is delay before the first retry
+	Backoff time.Duration
+
+	// MaxBackoff limits the delay. zero means no limit
+	MaxBackoff time.Duration
+
+	// Multiplier increases the delay after each retry. zero means 2
+	Multiplier float64
+
+	// Jitter randomizes the delay by the ratio. 0.2 means +-20%
+	Jitter float64
+
+	// Retryable returns whether the failure should be retried. nil means failures by error are retried,
+	// that is error returned by ToProviderCtx provider or error panicked by provider.
+	// panics of other values like "dependency cycle" or "Not Binded" of injector are not retried by default
+	Retryable func(err error) bool
+}
+
+// WithRetry makes provider of the binding called again by the policy when it fails
+func (b *Binding) WithRetry(policy RetryPolicy) *Binding {
+	b.retry = &policy
+	return b
+}
+
+func (r *RetryPolicy) delay(retry int) time.Duration {
+	multiplier := r.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	d := float64(r.Backoff)
+	for i := 1; i < retry; i++ {
+		d *= multiplier
+	}
+	if r.MaxBackoff > 0 && d > float64(r.MaxBackoff) {
+		d = float64(r.MaxBackoff)
+	}
+	if r.Jitter > 0 {
+		d += d * r.Jitter * (rand.Float64()*2 - 1)
+	}
+	return time.Duration(d)
+}
+
+func (r *RetryPolicy) shouldRetry(p interface{}) bool {
+	if isAborted(p) {
+		return false
+	}
+	if r.Retryable == nil {
+		_, isError := p.(error)
+		return isError
+	}
+	return r.Retryable(panicError(p))
+}
+
+// provide calls provider of the binding with retry policy
+func (b *Binding) provide(r *injectorContext) interface{} {
+	if b.retry == nil {
+		return b.provider(r)
+	}
+
+	for attempt := 1; ; attempt++ {
+		ret, failed := b.tryProvide(r)
+		if failed == nil {
+			return ret
+		}
+
+		if attempt >= b.retry.Attempts || !b.retry.shouldRetry(failed) {
+			panic(failed)
+		}
+
+		timer := time.NewTimer(b.retry.delay(attempt))
+		select {
+		case <-timer.C:
+		case <-r.ctxDone():
+			timer.Stop()
+			panic(fmt.Errorf("creation of %s is aborted : %w", b.tpe, r.ctx.Err()))
+		}
+	}
+}
+
+func (b *Binding) tryProvide(r *injectorContext) (ret interface{}, failed interface{}) {
+	defer func() {
+		failed = recover()
+	}()
+	return b.provider(r), nil
+}
+
+// resetOnFailure makes singleton can be created again if the creation fails
+func (b *Binding) resetOnFailure() {
+	if p := recover(); p != nil {
+		b.singletonOnce = sync.Once{}
+		panic(p)
+	}
+}
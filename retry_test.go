@@ -0,0 +1,118 @@
+package di_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+type database struct{}
+
+var errBooting = errors.New("database is booting")
+
+func failingDatabase(failures int, calls *int) func(ctx context.Context, injector di.Injector) (*database, error) {
+	return func(ctx context.Context, injector di.Injector) (*database, error) {
+		*calls++
+		if *calls <= failures {
+			return nil, errBooting
+		}
+		return &database{}, nil
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	calls := 0
+	impls := di.NewImplements()
+	impls.AddImplement("DB", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*database](binder).ToProviderCtx(failingDatabase(2, &calls)).WithRetry(di.RetryPolicy{
+			Attempts: 3,
+			Backoff:  time.Millisecond,
+			Jitter:   0.5,
+		}).AsEagerSingleton()
+	}))
+
+	injector := impls.NewInjector([]string{"DB"})
+	if di.GetInstance[*database](injector) == nil {
+		t.Error("database is nil")
+	}
+
+	if calls != 3 {
+		t.Errorf("calls = %d", calls)
+	}
+}
+
+func TestWithRetryNotRetryable(t *testing.T) {
+	calls := 0
+	impls := di.NewImplements()
+	impls.AddImplement("DB", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*database](binder).ToProviderCtx(failingDatabase(2, &calls)).WithRetry(di.RetryPolicy{
+			Attempts: 3,
+			Backoff:  time.Millisecond,
+			Retryable: func(err error) bool {
+				return !errors.Is(err, errBooting)
+			},
+		})
+	}))
+
+	injector := impls.NewInjector([]string{"DB"})
+	_, err := di.GetInstanceCtx[*database](context.Background(), injector)
+	if !errors.Is(err, errBooting) {
+		t.Errorf("unexpected error : %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("calls = %d", calls)
+	}
+}
+
+func TestWithRetryNotBinded(t *testing.T) {
+	calls := 0
+	impls := di.NewImplements()
+	impls.AddImplement("DB", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*database](binder).ToProvider(func(injector di.Injector) *database {
+			calls++
+			return injector.InjectAndCall(func(v Value1) *database {
+				return &database{}
+			}).(*database)
+		}).WithRetry(di.RetryPolicy{
+			Attempts: 3,
+			Backoff:  time.Millisecond,
+		})
+	}))
+
+	injector := impls.NewInjector([]string{"DB"})
+	_, err := di.GetInstanceCtx[*database](context.Background(), injector)
+	if err == nil {
+		t.Error("error is not returned")
+	}
+
+	if calls != 1 {
+		t.Errorf("calls = %d", calls)
+	}
+}
+
+func TestFailedSingletonNotCached(t *testing.T) {
+	calls := 0
+	impls := di.NewImplements()
+	impls.AddImplement("DB", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*database](binder).ToProviderCtx(failingDatabase(1, &calls))
+	}))
+
+	injector := impls.NewInjector([]string{"DB"})
+	_, err := di.GetInstanceCtx[*database](context.Background(), injector)
+	if !errors.Is(err, errBooting) {
+		t.Errorf("unexpected error : %v", err)
+	}
+
+	first := di.GetInstance[*database](injector)
+	if first == nil {
+		t.Fatal("failed singleton is cached")
+	}
+
+	if di.GetInstance[*database](injector) != first {
+		t.Error("singleton is created again")
+	}
+}
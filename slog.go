@@ -0,0 +1,133 @@
+package di
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// SlogOption changes which trace events SlogTracer logs
+type SlogOption func(tracer *slogTracer)
+
+type slogTracer struct {
+	logger     *slog.Logger
+	level      slog.Level
+	traceTypes map[TraceType]bool
+	patterns   []string
+}
+
+// SlogTraceTypes makes SlogTracer log only the trace types.
+// default is InstanceCreated only
+func SlogTraceTypes(traceTypes ...TraceType) SlogOption {
+	return func(tracer *slogTracer) {
+		tracer.traceTypes = map[TraceType]bool{}
+		for _, t := range traceTypes {
+			tracer.traceTypes[t] = true
+		}
+	}
+}
+
+// SlogTypeFilter makes SlogTracer log only types matching to one of patterns.
+// pattern is matched to type name like *db.Conn or package path like github.com/my/db.
+// pattern ending with /... matches all packages under the path
+func SlogTypeFilter(patterns ...string) SlogOption {
+	return func(tracer *slogTracer) {
+		tracer.patterns = append(tracer.patterns, patterns...)
+	}
+}
+
+// SlogTracer returns TraceCallback which logs trace events as structured records to logger
+func SlogTracer(logger *slog.Logger, level slog.Level, options ...SlogOption) TraceCallback {
+	tracer := &slogTracer{
+		logger:     logger,
+		level:      level,
+		traceTypes: map[TraceType]bool{InstanceCreated: true},
+	}
+	for _, opt := range options {
+		opt(tracer)
+	}
+	return tracer.trace
+}
+
+func packagePath(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map || t.Kind() == reflect.Chan {
+		t = t.Elem()
+	}
+	return t.PkgPath()
+}
+
+func matchTypePattern(pattern string, t reflect.Type) bool {
+	pkg := packagePath(t)
+	if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+		return pkg == prefix || strings.HasPrefix(pkg, prefix+"/")
+	}
+
+	if ok, _ := path.Match(pattern, t.String()); ok {
+		return true
+	}
+	ok, _ := path.Match(pattern, pkg)
+	return ok
+}
+
+func (r *slogTracer) accept(info *TraceInfo) bool {
+	if !r.traceTypes[info.TraceType] {
+		return false
+	}
+	if len(r.patterns) == 0 {
+		return true
+	}
+	for _, pattern := range r.patterns {
+		if matchTypePattern(pattern, info.RequestedType) {
+			return true
+		}
+	}
+	return false
+}
+
+func scopeOf(info *TraceInfo) string {
+	if info.IsEager {
+		return "eager"
+	}
+	if info.IsSingleton {
+		return "singleton"
+	}
+	return "prototype"
+}
+
+func (r *slogTracer) trace(info *TraceInfo) {
+	ctx := context.Background()
+	if !r.accept(info) || !r.logger.Enabled(ctx, r.level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("type", info.RequestedType.String()),
+		slog.String("scope", scopeOf(info)),
+	}
+	if info.Referer != nil {
+		attrs = append(attrs, slog.String("referer", info.Referer.String()))
+	}
+	if info.TraceType == InstanceCreated {
+		attrs = append(attrs, slog.Duration("elapsed", info.ElapsedTime))
+	}
+	if info.Module != "" {
+		attrs = append(attrs, slog.String("module", info.Module))
+	}
+	if info.Source != "" {
+		attrs = append(attrs, slog.String("source", info.Source))
+	}
+	if info.Worker != 0 {
+		attrs = append(attrs, slog.Int("worker", info.Worker))
+	}
+	r.logger.LogAttrs(ctx, r.level, info.TraceType.String(), attrs...)
+}
+
+// WithLogger logs lifecycle of the injector like creation, refresh of singletons
+// and errors while configuring modules to logger
+func WithLogger(logger *slog.Logger) InjectorOption {
+	return func(options *injectorOptions) {
+		options.logger = logger
+	}
+}
@@ -0,0 +1,105 @@
+package di_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/csgura/di"
+)
+
+type slogRepo struct{}
+type slogService struct{}
+
+func decodeLogs(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var ret []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		m := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &m); err != nil {
+			t.Fatal(err)
+		}
+		ret = append(ret, m)
+	}
+	return ret
+}
+
+func TestSlogTracer(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddImplement("Service", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*slogRepo](binder).ToConstructor(func() *slogRepo {
+			return &slogRepo{}
+		})
+
+		di.Bind[*slogService](binder).ToConstructor(func(repo *slogRepo) *slogService {
+			return &slogService{}
+		}).AsEagerSingleton()
+	}))
+
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	impls.NewInjectorWithTrace([]string{"Service"}, di.SlogTracer(logger, slog.LevelInfo, di.SlogTypeFilter("*di_test.slogRepo")))
+
+	logs := decodeLogs(t, &buf)
+	if len(logs) != 1 {
+		t.Fatalf("unexpected logs : %s", buf.String())
+	}
+
+	log := logs[0]
+	if log["msg"] != "Create Instance" || log["type"] != "*di_test.slogRepo" || log["referer"] != "*di_test.slogService" ||
+		log["scope"] != "singleton" || log["module"] != "Service" || !strings.Contains(log["source"].(string), "slog_test.go:") {
+		t.Errorf("unexpected log : %v", log)
+	}
+}
+
+func TestSlogTracerTraceTypes(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddImplement("Service", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*slogRepo](binder).ToConstructor(func() *slogRepo {
+			return &slogRepo{}
+		}).AsEagerSingleton()
+	}))
+
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	impls.NewInjectorWithTrace([]string{"Service"}, di.SlogTracer(logger, slog.LevelDebug))
+	if buf.Len() != 0 {
+		t.Errorf("disabled level is logged : %s", buf.String())
+	}
+
+	impls.NewInjectorWithTrace([]string{"Service"}, di.SlogTracer(logger, slog.LevelInfo, di.SlogTraceTypes(di.InstanceRequest), di.SlogTypeFilter("github.com/csgura/...")))
+	logs := decodeLogs(t, &buf)
+	if len(logs) != 1 || logs[0]["msg"] != "Request Instance" || logs[0]["scope"] != "eager" {
+		t.Errorf("unexpected logs : %s", buf.String())
+	}
+}
+
+func TestWithLogger(t *testing.T) {
+	impls := di.NewImplements()
+	impls.AddImplement("Service", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*slogRepo](binder).ToConstructor(func() *slogRepo {
+			panic("can't connect")
+		}).AsEagerSingleton()
+	}))
+
+	buf := bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	func() {
+		defer func() {
+			recover()
+		}()
+		impls.NewInjector([]string{"Service"}, di.WithLogger(logger))
+	}()
+
+	logs := decodeLogs(t, &buf)
+	if len(logs) != 1 || logs[0]["msg"] != "injector creation failed" || logs[0]["error"] != "can't connect" {
+		t.Errorf("unexpected logs : %s", buf.String())
+	}
+}
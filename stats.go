@@ -0,0 +1,126 @@
+package di
+
+import (
+	"expvar"
+	"reflect"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BindingStats is counters of a binding maintained by the injector
+type BindingStats struct {
+	Type      reflect.Type  `json:"-"`
+	Scope     string        `json:"scope"`
+	Requests  uint64        `json:"requests"`
+	Creations uint64        `json:"creations"`
+	Failures  uint64        `json:"failures"`
+	TotalTime time.Duration `json:"totalTime"`
+	MaxTime   time.Duration `json:"maxTime"`
+}
+
+type bindingCounters struct {
+	requests  atomic.Uint64
+	creations atomic.Uint64
+	failures  atomic.Uint64
+	totalTime atomic.Int64
+	maxTime   atomic.Int64
+}
+
+func (r *bindingCounters) created(elapsed time.Duration) {
+	r.creations.Add(1)
+	r.totalTime.Add(int64(elapsed))
+	for {
+		max := r.maxTime.Load()
+		if int64(elapsed) <= max || r.maxTime.CompareAndSwap(max, int64(elapsed)) {
+			return
+		}
+	}
+}
+
+// injectorStats has counters of bindings of an injector.
+// just-in-time bindings are created on each request, so their counters are kept by type
+type injectorStats struct {
+	lock     sync.Mutex
+	bindings map[reflect.Type]*Binding
+	jit      map[reflect.Type]*bindingCounters
+}
+
+// initStats makes counters for all bindings of the injector
+func (r *injectorImpl) initStats() {
+	r.stats = &injectorStats{bindings: map[reflect.Type]*Binding{}, jit: map[reflect.Type]*bindingCounters{}}
+	for t, p := range r.binder.providers {
+		p.counters = &bindingCounters{}
+		r.stats.bindings[t] = p
+	}
+}
+
+func (r *injectorStats) jitCounters(t reflect.Type) *bindingCounters {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := r.jit[t]
+	if ret == nil {
+		ret = &bindingCounters{}
+		r.jit[t] = ret
+	}
+	return ret
+}
+
+func statsOf(t reflect.Type, scope string, c *bindingCounters) BindingStats {
+	return BindingStats{
+		Type:      t,
+		Scope:     scope,
+		Requests:  c.requests.Load(),
+		Creations: c.creations.Load(),
+		Failures:  c.failures.Load(),
+		TotalTime: time.Duration(c.totalTime.Load()),
+		MaxTime:   time.Duration(c.maxTime.Load()),
+	}
+}
+
+func (r *injectorImpl) Stats() []BindingStats {
+	ret := []BindingStats{}
+	if r.stats == nil {
+		return ret
+	}
+
+	for t, p := range r.stats.bindings {
+		scope := "prototype"
+		if p.isEager {
+			scope = "eager"
+		} else if p.isSingleton {
+			scope = "singleton"
+		}
+		ret = append(ret, statsOf(t, scope, p.counters))
+	}
+
+	r.stats.lock.Lock()
+	for t, c := range r.stats.jit {
+		ret = append(ret, statsOf(t, "jit", c))
+	}
+	r.stats.lock.Unlock()
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Type.String() < ret[j].Type.String()
+	})
+	return ret
+}
+
+func (r *injectorContext) Stats() []BindingStats {
+	return r.injector.Stats()
+}
+
+// PublishStats publishes stats of the injector to expvar with the name.
+// it is map of type name to BindingStats.
+// like expvar.Publish, it panics if the name is already published, so it should be called once per name
+func PublishStats(name string, injector Injector) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		ret := map[string]BindingStats{}
+		for _, s := range injector.Stats() {
+			ret[s.Type.String()] = s
+		}
+		return ret
+	}))
+}
@@ -0,0 +1,85 @@
+package di_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/csgura/di"
+)
+
+var statsPublished atomic.Int64
+
+type heavyObject struct{}
+type statsService struct{}
+type jitObject struct {
+	Heavy *heavyObject `di:"inject"`
+}
+
+func TestStats(t *testing.T) {
+	fail := true
+	impls := di.NewImplements()
+	impls.AddImplement("Stats", di.BindFunc(func(binder *di.Binder) {
+		di.Bind[*heavyObject](binder).ToConstructor(func() *heavyObject {
+			time.Sleep(2 * time.Millisecond)
+			return &heavyObject{}
+		}).AsNonSingleton()
+
+		di.Bind[*statsService](binder).ToProvider(func(injector di.Injector) *statsService {
+			if fail {
+				fail = false
+				panic("not ready")
+			}
+			return &statsService{}
+		})
+	}))
+
+	injector := impls.NewInjector([]string{"Stats"})
+	for i := 0; i < 3; i++ {
+		di.GetInstance[*heavyObject](injector)
+	}
+
+	func() {
+		defer func() {
+			recover()
+		}()
+		di.GetInstance[*statsService](injector)
+	}()
+	di.GetInstance[*statsService](injector)
+	di.GetInstance[*statsService](injector)
+	injector.InjectAndCall(func(obj *jitObject) {})
+
+	stats := map[string]di.BindingStats{}
+	for _, s := range injector.Stats() {
+		stats[s.Type.String()] = s
+	}
+
+	heavy := stats["*di_test.heavyObject"]
+	if heavy.Scope != "prototype" || heavy.Requests != 4 || heavy.Creations != 4 || heavy.MaxTime < 2*time.Millisecond || heavy.TotalTime < 4*heavy.MaxTime/2 {
+		t.Errorf("unexpected stats : %+v", heavy)
+	}
+
+	service := stats["*di_test.statsService"]
+	if service.Scope != "singleton" || service.Requests != 3 || service.Creations != 1 || service.Failures != 1 {
+		t.Errorf("unexpected stats : %+v", service)
+	}
+
+	if jit := stats["*di_test.jitObject"]; jit.Scope != "jit" || jit.Creations != 1 {
+		t.Errorf("unexpected stats : %+v", jit)
+	}
+
+	// expvar can't publish same name twice, so the name is unique per run
+	name := fmt.Sprintf("di_test_stats_%d", statsPublished.Add(1))
+	di.PublishStats(name, injector)
+	published := map[string]map[string]interface{}{}
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &published); err != nil {
+		t.Fatal(err)
+	}
+
+	if published["*di_test.heavyObject"]["creations"] != float64(4) {
+		t.Errorf("unexpected published stats : %v", published)
+	}
+}